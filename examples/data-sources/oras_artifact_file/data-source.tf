@@ -1,4 +1,14 @@
 data "oras_artifact_file" "example" {
   name     = "localhost:5000/hello-artifact:v2"
   filename = "artifact.txt"
-}
\ No newline at end of file
+}
+
+data "oras_artifact_file" "encrypted" {
+  name     = "localhost:5000/hello-config:v1"
+  filename = "config.yaml.age"
+
+  decrypt {
+    type    = "age"
+    key_env = "CONFIG_AGE_IDENTITY"
+  }
+}
@@ -0,0 +1,4 @@
+data "oras_artifact_files" "example" {
+  name      = "localhost:5000/hello-config:v1"
+  filenames = ["config.yaml", "values.yaml"]
+}
@@ -0,0 +1,3 @@
+data "oras_artifact_metadata" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
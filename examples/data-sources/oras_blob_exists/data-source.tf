@@ -0,0 +1,4 @@
+data "oras_blob_exists" "example" {
+  repository = "localhost:5000/hello-artifact"
+  digest     = "sha256:b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+}
@@ -0,0 +1,8 @@
+data "oras_artifact" "example" {
+  name        = "localhost:5000/hello-artifact:v2"
+  output_path = "artifacts"
+}
+
+data "oras_cache_stats" "example" {
+  depends_on = [data.oras_artifact.example]
+}
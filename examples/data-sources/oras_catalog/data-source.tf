@@ -0,0 +1,7 @@
+data "oras_catalog" "example" {
+  registry = "registry.example.com"
+}
+
+output "repositories" {
+  value = data.oras_catalog.example.repositories
+}
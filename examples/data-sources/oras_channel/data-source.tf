@@ -0,0 +1,5 @@
+data "oras_channel" "example" {
+  repository                 = "localhost:5000/hello-artifact"
+  channel                    = "stable"
+  require_signature_referrer = true
+}
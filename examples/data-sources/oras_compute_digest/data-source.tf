@@ -0,0 +1,16 @@
+data "oras_compute_digest" "example" {
+  artifact_type = "application/vnd.example.config"
+
+  files {
+    path = "${path.module}/config.yaml"
+  }
+
+  files {
+    name    = "version.txt"
+    content = "1.0.0"
+  }
+}
+
+output "digest" {
+  value = data.oras_compute_digest.example.digest
+}
@@ -0,0 +1,7 @@
+data "oras_digest" "example" {
+  reference = "ghcr.io/org/app:v1.0.0"
+}
+
+output "pinned" {
+  value = "ghcr.io/org/app@${data.oras_digest.example.digest}"
+}
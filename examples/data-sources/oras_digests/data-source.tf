@@ -0,0 +1,7 @@
+data "oras_digests" "example" {
+  references = [
+    "localhost:5000/hello-artifact:v1",
+    "localhost:5000/hello-artifact:v2",
+  ]
+  concurrency = 2
+}
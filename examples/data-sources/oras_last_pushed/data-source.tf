@@ -0,0 +1,3 @@
+data "oras_last_pushed" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
@@ -0,0 +1,7 @@
+data "oras_layers_by_title" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
+
+output "config" {
+  value = data.oras_layers_by_title.example.contents["config.yaml"]
+}
@@ -0,0 +1,8 @@
+data "oras_manifest" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
+
+data "oras_manifest" "signature" {
+  reference              = "localhost:5000/hello-artifact:v2"
+  referrer_artifact_type = "application/vnd.cncf.notary.signature"
+}
@@ -0,0 +1,3 @@
+data "oras_reference_parse" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
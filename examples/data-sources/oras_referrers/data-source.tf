@@ -0,0 +1,4 @@
+data "oras_referrers" "example" {
+  reference     = "localhost:5000/hello-artifact:v2"
+  artifact_type = "application/vnd.cncf.notary.signature"
+}
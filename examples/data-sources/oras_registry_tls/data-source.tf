@@ -0,0 +1,7 @@
+data "oras_registry_tls" "example" {
+  registry = "registry.example.com"
+}
+
+output "certificate_expiry" {
+  value = data.oras_registry_tls.example.not_after
+}
@@ -0,0 +1,3 @@
+data "oras_sbom" "example" {
+  reference = "localhost:5000/hello-artifact:v2"
+}
@@ -0,0 +1,4 @@
+data "oras_semver_tag" "example" {
+  repository = "localhost:5000/hello-artifact"
+  constraint = "~> 1.2"
+}
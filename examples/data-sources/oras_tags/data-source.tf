@@ -0,0 +1,8 @@
+data "oras_tags" "example" {
+  repository = "ghcr.io/org/app"
+  regex      = "^v1\\."
+}
+
+output "tags" {
+  value = data.oras_tags.example.tags
+}
@@ -16,4 +16,13 @@ provider "oras" {
     password = "somepass"
   }
 
+  registry_auth {
+    address = "registry.internal.example.com"
+
+    exec {
+      command = "get-registry-token"
+      args    = ["--audience", "registry"]
+    }
+  }
+
 }
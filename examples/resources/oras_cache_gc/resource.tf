@@ -0,0 +1,7 @@
+resource "oras_cache_gc" "example" {
+  keep_manifests = 5
+
+  triggers = {
+    run = timestamp()
+  }
+}
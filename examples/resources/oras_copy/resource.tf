@@ -0,0 +1,5 @@
+resource "oras_copy" "example" {
+  source_reference      = "ghcr.io/org/app:1.0"
+  destination_reference = "registry.internal/mirror/app:1.0"
+  recursive             = true
+}
@@ -0,0 +1,13 @@
+resource "oras_push" "example" {
+  reference     = "ghcr.io/me/thing:1.0"
+  artifact_type = "application/vnd.example.config"
+
+  files {
+    path       = "${path.module}/config.yaml"
+    media_type = "application/vnd.example.config.layer.v1+yaml"
+  }
+
+  annotations = {
+    "org.opencontainers.image.source" = "https://github.com/me/thing"
+  }
+}
@@ -0,0 +1,4 @@
+resource "oras_tag" "example" {
+  reference = "ghcr.io/me/thing@sha256:9b2a3e4c1f6d8e0a7b5c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a"
+  tag       = "stable"
+}
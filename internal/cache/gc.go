@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Well-known paths of an OCI image layout.
+const (
+	blobsDir  = "blobs"
+	indexFile = "index.json"
+)
+
+// maxManifestBytes is the largest blob that is inspected while looking for
+// manifests in the cache.
+const maxManifestBytes = 4 * 1024 * 1024
+
+// GCResult holds the outcome of a garbage collection run.
+type GCResult struct {
+	// BlobsRemoved is the number of blobs removed from the cache.
+	BlobsRemoved int
+	// BytesReclaimed is the total size of the removed blobs.
+	BytesReclaimed int64
+}
+
+// node is the subset of the manifest and index formats required to walk the
+// graph stored in the cache.
+type node struct {
+	Config    *ocispec.Descriptor  `json:"config,omitempty"`
+	Layers    []ocispec.Descriptor `json:"layers,omitempty"`
+	Blobs     []ocispec.Descriptor `json:"blobs,omitempty"`
+	Manifests []ocispec.Descriptor `json:"manifests,omitempty"`
+}
+
+func (n node) successors() []ocispec.Descriptor {
+	var successors []ocispec.Descriptor
+	if n.Config != nil {
+		successors = append(successors, *n.Config)
+	}
+	successors = append(successors, n.Layers...)
+	successors = append(successors, n.Blobs...)
+	return append(successors, n.Manifests...)
+}
+
+type blob struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// GarbageCollect removes the blobs from the OCI layout at root that are not
+// reachable from the tagged manifests in its index, nor from the keep most
+// recently stored manifests.
+// Reachability is determined with a mark-and-sweep over the stored graph.
+func GarbageCollect(ctx context.Context, root string, keep int) (GCResult, error) {
+	blobs, err := listBlobs(filepath.Join(root, blobsDir))
+	if err != nil {
+		return GCResult{}, err
+	}
+
+	// collect all manifests, and the blobs they refer to
+	manifests := make(map[digest.Digest][]ocispec.Descriptor)
+	referenced := make(map[digest.Digest]bool)
+	for dgst, b := range blobs {
+		n, ok, err := readNode(b)
+		if err != nil {
+			return GCResult{}, err
+		}
+		if !ok {
+			continue
+		}
+		manifests[dgst] = n.successors()
+		for _, s := range manifests[dgst] {
+			referenced[s.Digest] = true
+		}
+	}
+
+	// roots are the tagged manifests and the most recent top-level manifests
+	index, err := readIndex(root)
+	if err != nil {
+		return GCResult{}, err
+	}
+
+	var roots []digest.Digest
+	for _, m := range index.Manifests {
+		if _, ok := m.Annotations[ocispec.AnnotationRefName]; ok {
+			roots = append(roots, m.Digest)
+		}
+	}
+
+	var candidates []digest.Digest
+	for dgst := range manifests {
+		if !referenced[dgst] {
+			candidates = append(candidates, dgst)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return blobs[candidates[i]].modTime.After(blobs[candidates[j]].modTime)
+	})
+	if keep < len(candidates) {
+		candidates = candidates[:keep]
+	}
+	roots = append(roots, candidates...)
+
+	// mark
+	marked := make(map[digest.Digest]bool)
+	var mark func(dgst digest.Digest)
+	mark = func(dgst digest.Digest) {
+		if marked[dgst] {
+			return
+		}
+		marked[dgst] = true
+		for _, s := range manifests[dgst] {
+			mark(s.Digest)
+		}
+	}
+	for _, dgst := range roots {
+		mark(dgst)
+	}
+
+	// drop the unreachable manifests from the index before removing them, so
+	// the layout never refers to missing blobs
+	var manifestsToKeep []ocispec.Descriptor
+	for _, m := range index.Manifests {
+		if marked[m.Digest] {
+			manifestsToKeep = append(manifestsToKeep, m)
+		}
+	}
+	if len(manifestsToKeep) != len(index.Manifests) {
+		index.Manifests = manifestsToKeep
+		if err := writeIndex(root, index); err != nil {
+			return GCResult{}, err
+		}
+	}
+
+	// sweep
+	var result GCResult
+	for dgst, b := range blobs {
+		if marked[dgst] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := os.Remove(b.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return result, err
+		}
+		result.BlobsRemoved++
+		result.BytesReclaimed += b.size
+	}
+
+	return result, nil
+}
+
+func listBlobs(dir string) (map[digest.Digest]blob, error) {
+	blobs := make(map[digest.Digest]blob)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		alg, encoded := filepath.Split(rel)
+		dgst := digest.NewDigestFromEncoded(digest.Algorithm(filepath.Clean(alg)), encoded)
+		if dgst.Validate() != nil {
+			// not a blob, leave it alone
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobs[dgst] = blob{path: path, size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+
+	return blobs, err
+}
+
+func readNode(b blob) (node, bool, error) {
+	if b.size > maxManifestBytes {
+		return node{}, false, nil
+	}
+
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return node{}, false, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return node{}, false, nil
+	}
+
+	var n node
+	if err := json.Unmarshal(data, &n); err != nil {
+		return node{}, false, nil
+	}
+
+	return n, len(n.successors()) > 0, nil
+}
+
+func readIndex(root string) (ocispec.Index, error) {
+	var index ocispec.Index
+
+	data, err := os.ReadFile(filepath.Join(root, indexFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return index, nil
+		}
+		return index, err
+	}
+
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("failed to parse %s: %w", indexFile, err)
+	}
+	return index, nil
+}
+
+func writeIndex(root string, index ocispec.Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	fp, err := os.CreateTemp(root, indexFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fp.Name())
+
+	if _, err := fp.Write(data); err != nil {
+		fp.Close()
+		return err
+	}
+	if err := fp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(fp.Name(), filepath.Join(root, indexFile))
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+func TestGarbageCollect(t *testing.T) {
+	root := t.TempDir()
+	store, err := oci.New(root)
+	if err != nil {
+		t.Fatal("oci.New() error =", err)
+	}
+	ctx := context.Background()
+
+	push := func(mediaType string, blob []byte) ocispec.Descriptor {
+		desc := ocispec.Descriptor{
+			MediaType: mediaType,
+			Digest:    digest.FromBytes(blob),
+			Size:      int64(len(blob)),
+		}
+		if err := store.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+			t.Fatal("Store.Push() error =", err)
+		}
+		return desc
+	}
+	pushManifest := func(config ocispec.Descriptor, layers ...ocispec.Descriptor) ocispec.Descriptor {
+		manifest := ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    config,
+			Layers:    layers,
+		}
+		blob, err := json.Marshal(manifest)
+		if err != nil {
+			t.Fatal("json.Marshal() error =", err)
+		}
+		return push(ocispec.MediaTypeImageManifest, blob)
+	}
+	age := func(desc ocispec.Descriptor, d time.Duration) {
+		path := filepath.Join(root, blobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+		mtime := time.Now().Add(-d)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal("os.Chtimes() error =", err)
+		}
+	}
+	exists := func(desc ocispec.Descriptor) bool {
+		ok, err := store.Exists(ctx, desc)
+		if err != nil {
+			t.Fatal("Store.Exists() error =", err)
+		}
+		return ok
+	}
+
+	config := push("application/vnd.test.config", []byte("{}"))
+	shared := push("test", []byte("shared"))
+	oldLayer := push("test", []byte("old"))
+	newLayer := push("test", []byte("new"))
+	orphan := push("test", []byte("orphan"))
+	tagged := push("test", []byte("tagged"))
+
+	oldManifest := pushManifest(config, shared, oldLayer)
+	age(oldManifest, time.Hour)
+	newManifest := pushManifest(config, shared, newLayer)
+	taggedManifest := pushManifest(config, tagged)
+	age(taggedManifest, 2*time.Hour)
+	if err := store.Tag(ctx, taggedManifest, "latest"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	result, err := GarbageCollect(ctx, root, 1)
+	if err != nil {
+		t.Fatal("GarbageCollect() error =", err)
+	}
+
+	// re-open the store to drop any in-memory state
+	if store, err = oci.New(root); err != nil {
+		t.Fatal("oci.New() error =", err)
+	}
+
+	for _, desc := range []ocispec.Descriptor{config, shared, newLayer, newManifest, tagged, taggedManifest} {
+		if !exists(desc) {
+			t.Errorf("blob %s was removed, want kept", desc.Digest)
+		}
+	}
+	removed := []ocispec.Descriptor{oldLayer, oldManifest, orphan}
+	var wantBytes int64
+	for _, desc := range removed {
+		if exists(desc) {
+			t.Errorf("blob %s was kept, want removed", desc.Digest)
+		}
+		wantBytes += desc.Size
+	}
+
+	if result.BlobsRemoved != len(removed) {
+		t.Errorf("GarbageCollect().BlobsRemoved = %d, want %d", result.BlobsRemoved, len(removed))
+	}
+	if result.BytesReclaimed != wantBytes {
+		t.Errorf("GarbageCollect().BytesReclaimed = %d, want %d", result.BytesReclaimed, wantBytes)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/singleflight"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Group deduplicates the concurrent fetches of the same content from the
+// origin by targets sharing the group. Safe for concurrent use.
+type Group struct {
+	sf singleflight.Group
+}
+
+// NewShared generates a new target storage with caching, where the content
+// missing from the cache is fetched from the origin only once across the
+// concurrent fetches of all targets sharing group.
+func NewShared(source oras.ReadOnlyTarget, cache content.Storage, group *Group) oras.ReadOnlyTarget {
+	t := New(source, cache)
+	switch t := t.(type) {
+	case *target:
+		t.group = group
+	case *referenceTarget:
+		t.group = group
+	}
+	return t
+}
+
+// fetchShared fetches the content identified by the descriptor into the
+// cache, sharing the fetch with concurrent callers, and then reads it from the
+// cache.
+//
+// The shared fetch runs under the context of the caller starting it. When that
+// caller is canceled, the others start the fetch again instead of failing
+// with its context error.
+func (t *target) fetchShared(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	for {
+		err := t.fetchOnce(ctx, target)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil || !(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			return nil, err
+		}
+	}
+	return t.cache.Fetch(ctx, target)
+}
+
+// fetchOnce fetches the content identified by the descriptor into the cache,
+// unless a concurrent caller is already fetching it, in which case it waits
+// for that fetch instead.
+func (t *target) fetchOnce(ctx context.Context, target ocispec.Descriptor) error {
+	_, err, _ := t.group.sf.Do(target.Digest.String(), func() (any, error) {
+		exists, err := t.cache.Exists(ctx, target)
+		if err != nil || exists {
+			return nil, err
+		}
+
+		rc, err := t.ReadOnlyTarget.Fetch(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		if err := t.cache.Push(ctx, target, rc); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
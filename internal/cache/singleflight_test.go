@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// slowTarget counts the fetches of its content, and blocks them until release
+// is closed.
+type slowTarget struct {
+	oras.ReadOnlyTarget
+	fetches int64
+	release chan struct{}
+}
+
+func (t *slowTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	atomic.AddInt64(&t.fetches, 1)
+	<-t.release
+	return t.ReadOnlyTarget.Fetch(ctx, desc)
+}
+
+func TestNewShared(t *testing.T) {
+	blob := []byte("hello world")
+	desc := ocispec.Descriptor{
+		MediaType: "test",
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+
+	ctx := context.Background()
+	origin := memory.New()
+	if err := origin.Push(ctx, desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+
+	source := &slowTarget{ReadOnlyTarget: origin, release: make(chan struct{})}
+	cache := memory.New()
+	var group Group
+
+	const n = 5
+	var wg sync.WaitGroup
+	results := make([][]byte, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = content.FetchAll(ctx, NewShared(source, cache, &group), desc)
+		}(i)
+	}
+
+	// wait for the first fetch to reach the origin before releasing it
+	for atomic.LoadInt64(&source.fetches) == 0 {
+		runtime.Gosched()
+	}
+	close(source.release)
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal("Fetch() error =", errs[i])
+		}
+		if !bytes.Equal(results[i], blob) {
+			t.Errorf("Fetch() = %v, want %v", results[i], blob)
+		}
+	}
+	if got := atomic.LoadInt64(&source.fetches); got != 1 {
+		t.Errorf("origin fetches = %d, want 1", got)
+	}
+}
+
+// cancelTarget blocks the first fetch of its content until the context of the
+// fetch is done.
+type cancelTarget struct {
+	oras.ReadOnlyTarget
+	fetches int64
+}
+
+func (t *cancelTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if atomic.AddInt64(&t.fetches, 1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return t.ReadOnlyTarget.Fetch(ctx, desc)
+}
+
+func TestNewShared_leaderCanceled(t *testing.T) {
+	blob := []byte("hello world")
+	desc := ocispec.Descriptor{
+		MediaType: "test",
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+
+	origin := memory.New()
+	if err := origin.Push(context.Background(), desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+
+	source := &cancelTarget{ReadOnlyTarget: origin}
+	cache := memory.New()
+	var group Group
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := content.FetchAll(leaderCtx, NewShared(source, cache, &group), desc)
+		leaderErr <- err
+	}()
+	for atomic.LoadInt64(&source.fetches) == 0 {
+		runtime.Gosched()
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		data, err := content.FetchAll(context.Background(), NewShared(source, cache, &group), desc)
+		waiter <- result{data, err}
+	}()
+
+	// let the waiter join the fetch of the leader before canceling it
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; err == nil {
+		t.Error("Fetch() of the canceled leader error = nil, want error")
+	}
+	got := <-waiter
+	if got.err != nil {
+		t.Fatal("Fetch() of the waiter error =", got.err)
+	}
+	if !bytes.Equal(got.data, blob) {
+		t.Errorf("Fetch() = %v, want %v", got.data, blob)
+	}
+}
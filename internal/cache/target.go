@@ -36,6 +36,8 @@ func (fn closer) Close() error {
 type target struct {
 	oras.ReadOnlyTarget
 	cache content.Storage
+	// group deduplicates concurrent fetches from the origin when set.
+	group *Group
 }
 
 // New generates a new target storage with caching.
@@ -61,6 +63,10 @@ func (t *target) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadC
 		return rc, nil
 	}
 
+	if t.group != nil {
+		return t.fetchShared(ctx, target)
+	}
+
 	if rc, err = t.ReadOnlyTarget.Fetch(ctx, target); err != nil {
 		return nil, err
 	}
@@ -111,6 +117,11 @@ func (t *target) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, err
 	return t.ReadOnlyTarget.Exists(ctx, desc)
 }
 
+// Cached returns true if the described content exists in the cache.
+func (t *target) Cached(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+	return t.cache.Exists(ctx, desc)
+}
+
 // Cache referenceTarget struct.
 type referenceTarget struct {
 	*target
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// auditLog appends a JSON line to a file for every artifact pulled, recording
+// which content entered the environment.
+type auditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// auditEntry is a line of the audit log.
+type auditEntry struct {
+	Time            time.Time `json:"time"`
+	Reference       string    `json:"reference"`
+	Digest          string    `json:"digest"`
+	BytesDownloaded int64     `json:"bytes_downloaded"`
+}
+
+// record appends an entry for reference, pulled at dgst.
+func (l *auditLog) record(reference, dgst string, bytesDownloaded int64) error {
+	if l == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(auditEntry{
+		Time:            time.Now().UTC(),
+		Reference:       reference,
+		Digest:          dgst,
+		BytesDownloaded: bytesDownloaded,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fp, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	// a single write per entry keeps the lines intact when several
+	// processes append to the same file
+	if _, err := fp.Write(append(data, '\n')); err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	l := &auditLog{path: filepath.Join(t.TempDir(), "audit.jsonl")}
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := l.record(fmt.Sprintf("registry.example.com/app:v%d", i), "sha256:abc", int64(i)); err != nil {
+				t.Error("record() error =", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	fp, err := os.Open(l.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %v", scanner.Text(), err)
+		}
+		if entry.Digest != "sha256:abc" || entry.Time.IsZero() {
+			t.Errorf("unexpected audit log entry %+v", entry)
+		}
+		seen[entry.Reference] = true
+	}
+	if len(seen) != n {
+		t.Errorf("audit log has %d distinct entries, want %d", len(seen), n)
+	}
+
+	var nilLog *auditLog
+	if err := nilLog.record("registry.example.com/app:v1", "sha256:abc", 0); err != nil {
+		t.Error("record() on a nil audit log error =", err)
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checksumsFile is the name of the file the checksums of the extracted files
+// are written to.
+const checksumsFile = "checksums.txt"
+
+// sha256sums returns the checksums of the regular files in dir and its sub
+// directories in the format of `sha256sum`, sorted by path. The checksums
+// file itself is skipped.
+func sha256sums(dir string) (string, error) {
+	var lines []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == checksumsFile {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		lines = append(lines, hex.EncodeToString(h.Sum(nil))+"  "+rel+"\n")
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// sort by path, which follows the fixed length checksum
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][64:] < lines[j][64:]
+	})
+	return strings.Join(lines, ""), nil
+}
@@ -0,0 +1,24 @@
+package provider
+
+import "testing"
+
+func TestSHA256Sums(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"b.txt":        "b",
+		"a/nested.txt": "nested",
+		checksumsFile:  "stale",
+		"a/empty.txt":  "",
+	})
+
+	got, err := sha256sums(dir)
+	if err != nil {
+		t.Fatal("sha256sums() error =", err)
+	}
+	want := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  a/empty.txt\n" +
+		"233562de1a0288b139c4fa40b7d189f806e906eeb048517aeb67f34ac0e2faf1  a/nested.txt\n" +
+		"3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d  b.txt\n"
+	if got != want {
+		t.Errorf("sha256sums() = %q, want %q", got, want)
+	}
+}
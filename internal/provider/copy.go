@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+)
+
+// mediaTypeDockerManifestList is the media type of a Docker multi-arch manifest list.
+const mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+// pullOptions holds the settings of a data source pulling an artifact.
+type pullOptions struct {
+	indexAnnotations map[string]string
+	platform         *ocispec.Platform
+	// expectedDigest is the digest the pulled manifest, or the index it is
+	// selected from, must have.
+	expectedDigest string
+	// verify verifies the manifest to pull, before any of its content is
+	// copied.
+	verify func(ctx context.Context, desc ocispec.Descriptor) error
+}
+
+// pullResult describes a pulled artifact.
+type pullResult struct {
+	// root is the descriptor the reference resolved to.
+	root ocispec.Descriptor
+	// desc is the descriptor of the copied manifest, which differs from root
+	// when a manifest was selected from an index.
+	desc            ocispec.Descriptor
+	bytesDownloaded int64
+}
+
+// expectedDigestSchema returns the schema of the digest a pulled artifact must
+// match.
+func expectedDigestSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Fail when the digest of the pulled manifest differs, before copying any of its content. " +
+			"When a manifest is selected from an index, the digest of the index is accepted as well.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateDigest,
+	}
+}
+
+// verifyDigest returns an error when neither the manifest nor the root of the
+// pulled artifact have the expected digest. Any digest matches when expected
+// is empty.
+func (r pullResult) verifyDigest(reference, expected string) error {
+	if expected == "" || expected == r.desc.Digest.String() || expected == r.root.Digest.String() {
+		return nil
+	}
+	return fmt.Errorf("%s resolved to digest %s, expected %s", reference, r.desc.Digest, expected)
+}
+
+// verificationError is the error of the verification of a manifest before it
+// is pulled, which is not worth retrying.
+type verificationError struct {
+	err error
+}
+
+func (e *verificationError) Error() string { return e.err.Error() }
+
+func (e *verificationError) Unwrap() error { return e.err }
+
+// withVerification makes the copy verify the selected manifest before copying
+// any content to the destination.
+func withVerification(copyOpts *oras.CopyOptions, reference string, opts pullOptions) {
+	if opts.expectedDigest == "" && opts.verify == nil {
+		return
+	}
+	mapRoot := copyOpts.MapRoot
+	copyOpts.MapRoot = func(ctx context.Context, src content.ReadOnlyStorage, root ocispec.Descriptor) (ocispec.Descriptor, error) {
+		desc := root
+		if mapRoot != nil {
+			var err error
+			if desc, err = mapRoot(ctx, src, root); err != nil {
+				return ocispec.Descriptor{}, err
+			}
+		}
+		if err := (pullResult{root: root, desc: desc}).verifyDigest(reference, opts.expectedDigest); err != nil {
+			return ocispec.Descriptor{}, &verificationError{err}
+		}
+		if opts.verify != nil {
+			if err := opts.verify(ctx, desc); err != nil {
+				return ocispec.Descriptor{}, &verificationError{err}
+			}
+		}
+		return desc, nil
+	}
+}
+
+// pull copies the artifact identified by reference into dst. When a lockfile
+// is configured, the locked digest is pulled instead of resolving the
+// reference again.
+func (c *clients) pull(ctx context.Context, reference string, dst oras.Target, opts pullOptions) (pullResult, error) {
+	var result pullResult
+
+	repo, err := c.newReadRepository(reference)
+	if err != nil {
+		return result, err
+	}
+
+	src, err := c.SourceTarget(reference, repo)
+	if err != nil {
+		return result, err
+	}
+
+	srcRef := repo.Reference.Reference
+	if dgst, ok := c.lockfile.lookup(reference); ok && !c.refreshes(reference) {
+		srcRef = dgst
+	}
+
+	// only failures to resolve the reference are explained, errors once the
+	// root is known are unrelated to the API the registry implements
+	var resolved bool
+	var stats copyStats
+	copyOpts := c.copyOptions(src, &stats)
+	copyOpts.MapRoot = func(ctx context.Context, src content.ReadOnlyStorage, root ocispec.Descriptor) (ocispec.Descriptor, error) {
+		resolved = true
+		result.root = root
+		if len(opts.indexAnnotations) > 0 {
+			return selectByAnnotations(opts.indexAnnotations)(ctx, src, root)
+		}
+		return root, nil
+	}
+	withTargetPlatform(&copyOpts, reference, opts.platform)
+	withVerification(&copyOpts, reference, opts)
+	if c.prefetch {
+		p := newPrefetchTarget(src, c.maxManifestSize)
+		defer p.wait()
+		copyOpts.FindSuccessors = p.findSuccessors
+		src = p
+	}
+
+	if result.desc, err = oras.Copy(ctx, src, srcRef, dst, srcRef, copyOpts); err != nil {
+		if !resolved {
+			err = explainResolveError(ctx, repo, err)
+		}
+		return result, err
+	}
+	result.bytesDownloaded = stats.bytesDownloaded
+
+	if err := c.lockfile.record(reference, result.root.Digest.String()); err != nil {
+		return result, fmt.Errorf("failed to update lockfile: %w", err)
+	}
+	if err := c.pinFile.record(reference, result.root.Digest.String()); err != nil {
+		return result, fmt.Errorf("failed to update pin file: %w", err)
+	}
+	if err := c.auditLog.record(reference, result.root.Digest.String(), result.bytesDownloaded); err != nil {
+		return result, fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return result, nil
+}
+
+// pullFiles pulls the artifact identified by reference into dir, retrying the
+// whole copy with a new file store when it fails, up to the copy retries of
+// the provider. When clean is set, dir is emptied before each retry.
+func (c *clients) pullFiles(ctx context.Context, reference, dir string, clean bool, opts pullOptions) (*file.Store, pullResult, error) {
+	backoff := copyRetryBackoff
+	for attempt := 1; ; attempt++ {
+		dst, err := file.New(dir)
+		if err != nil {
+			return nil, pullResult{}, err
+		}
+
+		result, err := c.pull(ctx, reference, dst, opts)
+		if err == nil {
+			return dst, result, nil
+		}
+		_ = dst.Close()
+
+		var verr *verificationError
+		if errors.As(err, &verr) {
+			return nil, result, err
+		}
+		if attempt > c.copyRetries || ctx.Err() != nil {
+			if attempt > 1 {
+				err = fmt.Errorf("failed to pull %s after %d attempts: %w", reference, attempt, err)
+			}
+			return nil, result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, result, fmt.Errorf("failed to pull %s after %d attempts: %w", reference, attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if clean {
+			if err := emptyDir(dir); err != nil {
+				return nil, result, err
+			}
+		}
+	}
+}
+
+// copyRetryBackoff is the delay before the first retry of a failed copy,
+// doubled for every next retry.
+var copyRetryBackoff = time.Second
+
+// emptyDir removes the content of dir.
+func emptyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedTarget is implemented by targets serving content from a local cache.
+type cachedTarget interface {
+	Cached(ctx context.Context, desc ocispec.Descriptor) (bool, error)
+}
+
+// copyStats collects statistics about the content copied by oras.Copy.
+type copyStats struct {
+	mu              sync.Mutex
+	cacheHits       map[digest.Digest]bool
+	bytesDownloaded int64
+}
+
+// cacheCounters aggregates the cache hits and misses of all copies made by
+// the provider.
+type cacheCounters struct {
+	mu     sync.Mutex
+	hits   int64
+	misses int64
+}
+
+func (c *cacheCounters) record(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+func (c *cacheCounters) get() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// copyOptions returns the options to copy from src, with hooks recording the
+// copied content in stats.
+func (c *clients) copyOptions(src oras.ReadOnlyTarget, stats *copyStats) oras.CopyOptions {
+	opts := oras.DefaultCopyOptions
+	opts.MaxMetadataBytes = c.maxManifestSize
+	opts.Concurrency = c.copyConcurrency
+	stats.cacheHits = make(map[digest.Digest]bool)
+
+	opts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		cache, ok := src.(cachedTarget)
+		if !ok {
+			return nil
+		}
+		hit, err := cache.Cached(ctx, desc)
+		if err != nil {
+			return err
+		}
+		c.cacheCounters.record(hit)
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		stats.cacheHits[desc.Digest] = hit
+		return nil
+	}
+	opts.PostCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		if !stats.cacheHits[desc.Digest] {
+			stats.bytesDownloaded += desc.Size
+		}
+		return nil
+	}
+
+	return opts
+}
+
+// selectByAnnotations returns a MapRoot function selecting the first manifest
+// of an index carrying all the given annotations.
+func selectByAnnotations(annotations map[string]string) func(context.Context, content.ReadOnlyStorage, ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return func(ctx context.Context, src content.ReadOnlyStorage, root ocispec.Descriptor) (ocispec.Descriptor, error) {
+		if root.MediaType != ocispec.MediaTypeImageIndex && root.MediaType != mediaTypeDockerManifestList {
+			return ocispec.Descriptor{}, fmt.Errorf("cannot select a manifest by annotations, %s is not an index but %s", root.Digest, root.MediaType)
+		}
+
+		data, err := content.FetchAll(ctx, src, root)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		var index ocispec.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+
+		for _, m := range index.Manifests {
+			if hasAnnotations(m, annotations) {
+				return m, nil
+			}
+		}
+		return ocispec.Descriptor{}, fmt.Errorf("no manifest in index %s matches the annotations %v", root.Digest, annotations)
+	}
+}
+
+func hasAnnotations(desc ocispec.Descriptor, annotations map[string]string) bool {
+	for k, v := range annotations {
+		if value, ok := desc.Annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
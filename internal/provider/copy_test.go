@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestPullFiles_retries(t *testing.T) {
+	var requests int64
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/manifests/") {
+			atomic.AddInt64(&requests, 1)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+
+	defer func(backoff time.Duration) { copyRetryBackoff = backoff }(copyRetryBackoff)
+	copyRetryBackoff = time.Millisecond
+
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "stale")
+	if err := os.WriteFile(stale, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &clients{client: &auth.Client{Client: srv.Client()}, copyRetries: 2}
+	_, _, err = c.pullFiles(context.Background(), u.Host+"/app:v1", dir, true, pullOptions{})
+	if err == nil {
+		t.Fatal("pullFiles() error = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("pullFiles() error = %v, want the attempt count", err)
+	}
+	if got := atomic.LoadInt64(&requests); got != 3 {
+		t.Errorf("manifest requests = %d, want 3", got)
+	}
+	if _, err := os.Stat(stale); err == nil {
+		t.Error("pullFiles() did not clean the directory before retrying")
+	}
+}
+
+func TestPullResultVerifyDigest(t *testing.T) {
+	index := digest.FromString("index")
+	manifest := digest.FromString("manifest")
+	result := pullResult{
+		root: ocispec.Descriptor{Digest: index},
+		desc: ocispec.Descriptor{Digest: manifest},
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{name: "manifest", expected: manifest.String()},
+		{name: "index", expected: index.String()},
+		{name: "mismatch", expected: digest.FromString("other").String(), wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := result.verifyDigest("registry.example.com/app:v1", tt.expected); (err != nil) != tt.wantErr {
+			t.Errorf("verifyDigest(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCopyOptions_concurrency(t *testing.T) {
+	var stats copyStats
+	if got := (&clients{}).copyOptions(nil, &stats).Concurrency; got != 0 {
+		t.Errorf("copyOptions().Concurrency = %d, want the default of oras-go", got)
+	}
+	if got := (&clients{copyConcurrency: 8}).copyOptions(nil, &stats).Concurrency; got != 8 {
+		t.Errorf("copyOptions().Concurrency = %d, want 8", got)
+	}
+}
+
+func TestWithVerification(t *testing.T) {
+	src := memory.New()
+	ctx := context.Background()
+
+	layer := pushBlob(t, src, "application/vnd.test.file", []byte("hello"))
+	layer.Annotations = map[string]string{ocispec.AnnotationTitle: "hello.txt"}
+	manifest := pushManifest(t, src, pushBlob(t, src, "application/vnd.test.config", []byte("{}")), layer)
+	if err := src.Tag(ctx, manifest, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	errRejected := errors.New("rejected")
+	tests := []struct {
+		name    string
+		opts    pullOptions
+		wantErr bool
+	}{
+		{name: "expected digest", opts: pullOptions{expectedDigest: manifest.Digest.String()}},
+		{name: "digest mismatch", opts: pullOptions{expectedDigest: digest.FromString("other").String()}, wantErr: true},
+		{name: "rejected", opts: pullOptions{verify: func(context.Context, ocispec.Descriptor) error { return errRejected }}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dst, err := file.New(dir)
+			if err != nil {
+				t.Fatal("file.New() error =", err)
+			}
+			defer dst.Close()
+
+			opts := oras.DefaultCopyOptions
+			withVerification(&opts, "app:v1", tt.opts)
+			_, err = oras.Copy(ctx, src, "v1", dst, "v1", opts)
+
+			_, statErr := os.Stat(filepath.Join(dir, "hello.txt"))
+			if !tt.wantErr {
+				if err != nil || statErr != nil {
+					t.Errorf("oras.Copy() error = %v, hello.txt error = %v, want the file", err, statErr)
+				}
+				return
+			}
+			var verr *verificationError
+			if !errors.As(err, &verr) {
+				t.Errorf("oras.Copy() error = %v, want a verification error", err)
+			}
+			if tt.opts.verify != nil && !errors.Is(err, errRejected) {
+				t.Errorf("oras.Copy() error = %v, want %v", err, errRejected)
+			}
+			if statErr == nil {
+				t.Error("oras.Copy() wrote the content before verifying it")
+			}
+		})
+	}
+}
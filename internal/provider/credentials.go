@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// credentialFunc returns the credential of a registry each time it is needed,
+// for credentials which are obtained dynamically.
+type credentialFunc func(ctx context.Context) (auth.Credential, error)
+
+// providerSetToCredentialFuncs returns the dynamic credentials configured by
+// the registry_auth blocks, keyed by hostname.
+func providerSetToCredentialFuncs(authList *schema.Set) (map[string]credentialFunc, error) {
+	funcs := make(map[string]credentialFunc)
+
+	for _, authMap := range registryAuthBlocks(authList) {
+		hostname := convertToHostname(authMap["address"].(string))
+
+		if authMap["anonymous"].(bool) {
+			continue
+		}
+
+		if v, ok := authMap["exec"].([]any); ok && len(v) > 0 && v[0] != nil {
+			e, err := expandExecCredential(v[0].(map[string]any))
+			if err != nil {
+				return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+			}
+			funcs[hostname] = e.credential
+		}
+
+		if v, ok := authMap["github_oidc"].([]any); ok && len(v) > 0 {
+			m, _ := v[0].(map[string]any)
+			if m == nil {
+				m = map[string]any{"audience": "", "username": ""}
+			}
+			g, err := expandGitHubOIDCCredential(m)
+			if err != nil {
+				return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+			}
+			funcs[hostname] = g.credential
+		}
+
+		if v, ok := authMap["ecr"].([]any); ok && len(v) > 0 {
+			m, _ := v[0].(map[string]any)
+			if m == nil {
+				m = map[string]any{"region": "", "profile": ""}
+			}
+			e, err := expandECRCredential(hostname, m)
+			if err != nil {
+				return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+			}
+			funcs[hostname] = e.credential
+		}
+
+		if authMap["gcp"].(bool) {
+			g, err := expandGCPCredential()
+			if err != nil {
+				return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+			}
+			funcs[hostname] = g.credential
+		}
+
+		if v, ok := authMap["keychain"].([]any); ok && len(v) > 0 && v[0] != nil {
+			k, err := expandKeychainCredential(hostname, v[0].(map[string]any))
+			if err != nil {
+				return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+			}
+			funcs[hostname] = k.credential
+		}
+	}
+
+	return funcs, nil
+}
+
+// credentialModes returns the options the registry_auth block authMap sets
+// to obtain its credential, e.g. `username` or `ecr`. A refresh_token next to
+// a username is part of the same static credential, so it is not reported
+// separately.
+func credentialModes(authMap map[string]any) []string {
+	var modes []string
+	if username, _ := authMap["username"].(string); username != "" {
+		modes = append(modes, "username")
+	} else if refreshToken, _ := authMap["refresh_token"].(string); refreshToken != "" {
+		modes = append(modes, "refresh_token")
+	}
+	if anonymous, _ := authMap["anonymous"].(bool); anonymous {
+		modes = append(modes, "anonymous")
+	}
+	if gcp, _ := authMap["gcp"].(bool); gcp {
+		modes = append(modes, "gcp")
+	}
+	for _, key := range []string{"exec", "github_oidc", "ecr", "keychain"} {
+		if v, ok := authMap[key].([]any); ok && len(v) > 0 {
+			modes = append(modes, key)
+		}
+	}
+	return modes
+}
+
+// checkCredentialModes fails when a registry_auth block configures more than
+// one way to obtain credentials, as only one of them would be used.
+func checkCredentialModes(authList *schema.Set) error {
+	for _, registryAuth := range authList.List() {
+		authMap := registryAuth.(map[string]any)
+		if modes := credentialModes(authMap); len(modes) > 1 {
+			return fmt.Errorf("registry_auth block for '%s' configures multiple credentials: %s, only one of them can be set",
+				authMap["address"].(string), strings.Join(modes, ", "))
+		}
+	}
+	return nil
+}
+
+// hasCredentialFunc reports whether the registry_auth block authMap obtains
+// its credential dynamically.
+func hasCredentialFunc(authMap map[string]any) bool {
+	if gcp, ok := authMap["gcp"].(bool); ok && gcp {
+		return true
+	}
+	for _, key := range []string{"exec", "github_oidc", "ecr", "keychain"} {
+		if v, ok := authMap[key].([]any); ok && len(v) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// execCredential obtains credentials by running a command, similar to the
+// exec credential plugins of kubectl.
+type execCredential struct {
+	command string
+	args    []string
+	env     map[string]string
+
+	mu        sync.Mutex
+	cached    auth.Credential
+	expiresAt time.Time
+}
+
+func expandExecCredential(m map[string]any) (*execCredential, error) {
+	command, err := exec.LookPath(m["command"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("exec command not found: %w", err)
+	}
+	return &execCredential{
+		command: command,
+		args:    expandStringList(m["args"].([]any)),
+		env:     expandStringMap(m["env"].(map[string]any)),
+	}, nil
+}
+
+// credential runs the command, unless the credential it returned before has
+// not expired yet.
+func (e *execCredential) credential(ctx context.Context) (auth.Credential, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.expiresAt.IsZero() && time.Now().Before(e.expiresAt) {
+		return e.cached, nil
+	}
+
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Env = os.Environ()
+	for k, v := range e.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return auth.EmptyCredential, fmt.Errorf("exec credential command %s failed: %v: %s", e.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	cred, expiresAt, err := parseExecCredential(stdout.Bytes())
+	if err != nil {
+		return auth.EmptyCredential, fmt.Errorf("exec credential command %s: %w", e.command, err)
+	}
+	e.cached, e.expiresAt = cred, expiresAt
+	return cred, nil
+}
+
+// parseExecCredential parses the output of an exec credential command, either
+// a JSON object with a username and password or a token, or the token itself.
+func parseExecCredential(out []byte) (auth.Credential, time.Time, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("no credential in output")
+	}
+	if out[0] != '{' {
+		return auth.Credential{AccessToken: string(out)}, time.Time{}, nil
+	}
+
+	var v struct {
+		Username  string    `json:"username"`
+		Password  string    `json:"password"`
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("failed to parse credential: %w", err)
+	}
+
+	switch {
+	case v.Token != "":
+		return auth.Credential{AccessToken: v.Token}, v.ExpiresAt, nil
+	case v.Username != "" && v.Password != "":
+		return auth.Credential{Username: v.Username, Password: v.Password}, v.ExpiresAt, nil
+	default:
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("credential has neither a token nor a username and password")
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestParseExecCredential(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name          string
+		out           string
+		want          auth.Credential
+		wantExpiresAt time.Time
+		wantErr       bool
+	}{
+		{name: "plain token", out: "abc123\n", want: auth.Credential{AccessToken: "abc123"}},
+		{name: "token", out: `{"token": "abc123", "expires_at": "2030-01-02T03:04:05Z"}`, want: auth.Credential{AccessToken: "abc123"}, wantExpiresAt: expiresAt},
+		{name: "username and password", out: `{"username": "user", "password": "pass"}`, want: auth.Credential{Username: "user", Password: "pass"}},
+		{name: "empty", out: "\n", wantErr: true},
+		{name: "invalid json", out: `{"token": `, wantErr: true},
+		{name: "no credential", out: `{"username": "user"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotExpiresAt, err := parseExecCredential([]byte(tt.out))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExecCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || !gotExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("parseExecCredential() = %v, %v, want %v, %v", got, gotExpiresAt, tt.want, tt.wantExpiresAt)
+			}
+		})
+	}
+}
+
+func TestExecCredential(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "get-token")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho run >> \""+counter+"\"\necho \"{\\\"username\\\": \\\"$1\\\", \\\"password\\\": \\\"$SECRET\\\"}\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := expandExecCredential(map[string]any{
+		"command": script,
+		"args":    []any{"user"},
+		"env":     map[string]any{"SECRET": "pass"},
+	})
+	if err != nil {
+		t.Fatal("expandExecCredential() error =", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := e.credential(context.Background())
+		if err != nil {
+			t.Fatal("credential() error =", err)
+		}
+		if want := (auth.Credential{Username: "user", Password: "pass"}); got != want {
+			t.Errorf("credential() = %v, want %v", got, want)
+		}
+	}
+
+	// without expiry, the command runs each time the credential is needed
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(runs) / len("run\n"); got != 2 {
+		t.Errorf("command ran %d times, want 2", got)
+	}
+
+	if _, err := expandExecCredential(map[string]any{"command": "does-not-exist-oras", "args": []any{}, "env": map[string]any{}}); err == nil {
+		t.Error("expandExecCredential() error = nil, want error for a missing command")
+	}
+}
+
+func TestCheckCredentialModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   map[string]any
+		wantErr string
+	}{
+		{name: "static", block: map[string]any{"username": "user", "password": "secret"}},
+		{name: "exec", block: map[string]any{"exec": []any{map[string]any{"command": "get-token"}}}},
+		{name: "gcp", block: map[string]any{"gcp": true}},
+		{
+			name:    "static and exec",
+			block:   map[string]any{"username": "user", "password": "secret", "exec": []any{map[string]any{"command": "get-token"}}},
+			wantErr: "username, exec",
+		},
+		{
+			name:    "gcp and ecr",
+			block:   map[string]any{"gcp": true, "ecr": []any{map[string]any{"region": "eu-west-1"}}},
+			wantErr: "gcp, ecr",
+		},
+		{
+			name:    "anonymous and refresh token",
+			block:   map[string]any{"anonymous": true, "refresh_token": "token"},
+			wantErr: "refresh_token, anonymous",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.block["address"] = "registry.example.com"
+			err := checkCredentialModes(registryAuthSet(t, tt.block))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkCredentialModes() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkCredentialModes() error = %v, want the modes %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,10 +2,16 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"oras.land/oras-go/v2"
-	"oras.land/oras-go/v2/content/file"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func dataSourceOrasArtifact() *schema.Resource {
@@ -21,10 +27,169 @@ func dataSourceOrasArtifact() *schema.Resource {
 				Required:    true,
 			},
 			"output_path": {
-				Description: "The output path of the artifact.",
+				Description: "The output path of the artifact. Files are written by their title, and gzip compressed tar layers without title, e.g. the layers of a container image, are extracted into it.",
 				Type:        schema.TypeString,
 				Required:    true,
 			},
+			"index_annotations": {
+				Description: "When the artifact is an index, select the first manifest of the index having all these annotations.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"platform":        platformSchema(),
+			"expected_digest": expectedDigestSchema(),
+			"size": {
+				Description: "The total size in bytes of the artifact in the registry, i.e. of its manifest, config and layers.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"size_human": {
+				Description: "The total size of the artifact in a human readable format, e.g. `1.2 GiB`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"os": {
+				Description: "The operating system of the image, read from its config. Not set for artifacts which are not images.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"architecture": {
+				Description: "The CPU architecture of the image, read from its config. Not set for artifacts which are not images.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"variant": {
+				Description: "The variant of the CPU architecture of the image, read from its config. Not set for artifacts which are not images.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"hardlink_duplicates": {
+				Description: "Extract files with identical content as hardlinks to a single copy, to save disk space. " +
+					"Falls back to separate copies on filesystems not supporting hardlinks.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"flatten": {
+				Description: "Extract all files directly into `output_path`, named by the last component of their path, without the directory structure of the artifact. " +
+					"Fails when two files have the same name.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"write_checksums": {
+				Description: "Write the SHA-256 checksums of the extracted files to `checksums.txt` in `output_path`, in the format of `sha256sum` and sorted by path, " +
+					"e.g. to verify them with `sha256sum -c`. The file itself is not included in `file_count`, `tree` and `uncompressed_size`.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"checksums": {
+				Description: "The content of `checksums.txt`. Only set when `write_checksums` is enabled.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"hardlinked_files": {
+				Description: "The number of extracted files replaced by a hardlink when `hardlink_duplicates` is set.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"expected_file_count": {
+				Description:  "Fail when the number of files in `output_path` after extraction differs, to catch truncated or modified artifacts.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"file_count": {
+				Description: "The number of files in `output_path` after extraction, including symlinks but not directories.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"uncompressed_size": {
+				Description: "The total size in bytes of the files in `output_path` after extraction, i.e. the disk footprint of the artifact, " +
+					"unlike the compressed size of its layers in the registry. Hardlinked files are counted each time.",
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"tree": {
+				Description: "The directory structure of `output_path` after extraction, as a list of entries with a `name`, `path`, `type` and `size`. " +
+					"The entries of a directory are nested in its `children`, up to 8 levels deep.",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     treeSchema(maxTreeDepth),
+			},
+			"total_size": {
+				Description: "The total size in bytes of the artifact in the registry, i.e. of its manifest, config and layers.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"layer_count": {
+				Description: "The number of layers of the artifact.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"layers": {
+				Description: "The layers of the artifact.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"media_type": {
+							Description: "The media type of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"digest": {
+							Description: "The digest of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"size": {
+							Description: "The size in bytes of the layer.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"layer_media_types": {
+				Description: "The distinct media types of the layers of the artifact, e.g. to assert it only contains expected types of content.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"has_config": {
+				Description: "Whether the manifest has a config, false for artifacts using the empty config descriptor (`application/vnd.oci.empty.v1+json`).",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"annotations": {
+				Description: "The annotations of the manifest, e.g. `org.opencontainers.image.created`.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"merge_config_labels": {
+				Description: "Merge the labels of the image config, e.g. set with `LABEL` in a Dockerfile, into `annotations`. " +
+					"The annotations of the manifest take precedence over labels with the same key.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"config_path": {
+				Description: "Write the config blob of the artifact to this path, whatever its media type, e.g. to read the config of a custom artifact type. " +
+					"Configs of media types other than the image config are otherwise ignored.",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"config_media_type": {
+				Description: "The media type of the config of the artifact.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"verify_provenance": provenanceSchema(),
+			"bytes_downloaded": {
+				Description: "The number of bytes fetched from the registry while reading the artifact, excluding content served from the local cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -35,27 +200,127 @@ func dataSourceOrasArtifactRead(ctx context.Context, d *schema.ResourceData, met
 	reference := d.Get("name").(string)
 	outputPath := d.Get("output_path").(string)
 
-	repo, err := opts.NewRepository(reference)
+	pullOpts := pullOptions{
+		indexAnnotations: expandStringMap(d.Get("index_annotations").(map[string]any)),
+		platform:         expandPlatform(d.Get("platform").([]any)),
+		expectedDigest:   d.Get("expected_digest").(string),
+	}
+	var provenanceErr error
+	if v, ok := d.GetOk("verify_provenance"); ok {
+		m := v.([]any)[0].(map[string]any)
+		repo, err := opts.newReadRepository(reference)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// verified before any content is written to output_path
+		pullOpts.verify = func(ctx context.Context, desc ocispec.Descriptor) error {
+			provenanceErr = verifyProvenance(ctx, repo, desc, m["artifact_type"].(string), m["builder_id"].(string))
+			return provenanceErr
+		}
+	}
+
+	dst, result, err := opts.pullFiles(ctx, reference, outputPath, false, pullOpts)
+	if provenanceErr != nil {
+		return provenanceDiagnostics(reference, provenanceErr)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the manifest, config and untitled layers are kept in memory by the file store
+	manifest, err := fetchManifest(ctx, dst, result.desc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if err := extractLayers(ctx, dst, outputPath, manifest.Layers); err != nil {
+		return diag.FromErr(err)
+	}
 
-	src, err := opts.CachedTarget(repo)
+	platform, err := fetchPlatform(ctx, dst, result.desc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if platform != nil {
+		_ = d.Set("os", platform.OS)
+		_ = d.Set("architecture", platform.Architecture)
+		_ = d.Set("variant", platform.Variant)
+	}
 
-	dst, err := file.New(outputPath)
+	annotations := manifest.Annotations
+	if d.Get("merge_config_labels").(bool) {
+		if annotations, err = mergeConfigLabels(ctx, dst, &manifest.Config, annotations); err != nil {
+			return diag.Errorf("failed to read the config of %s: %v", reference, err)
+		}
+	}
+
+	if path, ok := d.GetOk("config_path"); ok {
+		if err := writeConfig(ctx, dst, manifest.Config, path.(string)); err != nil {
+			return diag.Errorf("failed to write the config of %s: %v", reference, err)
+		}
+	}
+
+	hardlinked := 0
+	if d.Get("hardlink_duplicates").(bool) {
+		if hardlinked, err = hardlinkDuplicates(outputPath, manifest.Layers); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("flatten").(bool) {
+		if err := flattenDir(outputPath); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var checksums string
+	if d.Get("write_checksums").(bool) {
+		// the checksums file of a previous read is replaced
+		if err := os.Remove(filepath.Join(outputPath, checksumsFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return diag.FromErr(err)
+		}
+		if checksums, err = sha256sums(outputPath); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	fileCount, uncompressedSize, err := countFiles(outputPath)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	if expected, ok := d.GetOkExists("expected_file_count"); ok && expected.(int) != fileCount {
+		return diag.Errorf("%s extracted %d files into %s, expected %d", reference, fileCount, outputPath, expected.(int))
+	}
 
-	desc, err := oras.Copy(ctx, src, repo.Reference.Reference, dst, repo.Reference.Reference, oras.DefaultCopyOptions)
+	tree, err := buildTree(outputPath, "", maxTreeDepth)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(desc.Digest.String())
+	if d.Get("write_checksums").(bool) {
+		// written after counting the files, so it isn't counted itself
+		if err := os.WriteFile(filepath.Join(outputPath, checksumsFile), []byte(checksums), 0o644); err != nil {
+			return diag.FromErr(err)
+		}
+		_ = d.Set("checksums", checksums)
+	}
+
+	_ = d.Set("file_count", fileCount)
+	_ = d.Set("uncompressed_size", uncompressedSize)
+	_ = d.Set("tree", tree)
+	_ = d.Set("hardlinked_files", hardlinked)
+	size := artifactSize(result.desc, manifest)
+	_ = d.Set("size", size)
+	_ = d.Set("size_human", humanize.IBytes(uint64(size)))
+	_ = d.Set("total_size", size)
+	_ = d.Set("layer_count", len(manifest.Layers))
+	_ = d.Set("layers", flattenLayers(manifest.Layers))
+	_ = d.Set("layer_media_types", layerMediaTypes(manifest.Layers))
+	_ = d.Set("has_config", hasConfig(&manifest.Config))
+	_ = d.Set("config_media_type", manifest.Config.MediaType)
+	_ = d.Set("annotations", annotations)
+	_ = d.Set("bytes_downloaded", result.bytesDownloaded)
+
+	d.SetId(result.desc.Digest.String())
 
 	return nil
 }
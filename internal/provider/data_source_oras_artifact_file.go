@@ -5,13 +5,16 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/hex"
-	"oras.land/oras-go/v2"
-	"oras.land/oras-go/v2/content/file"
+	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 
+	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceOrasArtifactFile() *schema.Resource {
@@ -27,8 +30,75 @@ func dataSourceOrasArtifactFile() *schema.Resource {
 				Required:    true,
 			},
 			"filename": {
-				Type:     schema.TypeString,
-				Required: true,
+				Description:  "The name of the file to read.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"filename", "glob"},
+			},
+			"glob": {
+				Description: "A pattern matching the files to read, e.g. `*.yaml`. The matching files are returned in `files` and `files_base64`, " +
+					"or in `content` and `content_base64` when `single` is set.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGlob,
+			},
+			"single": {
+				Description:  "Require the `glob` to match exactly one file, and return it in `content` and `content_base64`.",
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"glob"},
+			},
+			"min_size": {
+				Description:  "Only match the files of the `glob` of at least this size in bytes.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"glob"},
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_size": {
+				Description:  "Only match the files of the `glob` of at most this size in bytes, e.g. to skip large binaries.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"glob"},
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"allow_no_match": {
+				Description:  "Return empty `files` and `files_base64` when the `glob` matches no file, instead of failing.",
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"glob"},
+			},
+			"matched": {
+				Description: "Whether the `glob` matched any file. Always true when reading a `filename`.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"index_annotations": {
+				Description: "When the artifact is an index, select the first manifest of the index having all these annotations.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"platform":        platformSchema(),
+			"expected_digest": expectedDigestSchema(),
+			"use_ramdisk": {
+				Description: "Extract the artifact into a tmpfs-backed temporary directory, e.g. `/dev/shm`. " +
+					"Only supported on Linux, falls back to the regular temporary directory when no tmpfs is available.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"decrypt": decryptSchema(),
+			"files": {
+				Description: "Raw content of the files matching the `glob`, as UTF-8 encoded strings keyed by file name.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"files_base64": {
+				Description: "Base64 encoded content of the files matching the `glob`, keyed by file name.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			"content": {
 				Description: "Raw content of the file that was read, as UTF-8 encoded string.",
@@ -40,6 +110,32 @@ func dataSourceOrasArtifactFile() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"size": {
+				Description: "The total size in bytes of the artifact in the registry, i.e. of its manifest, config and layers.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"size_human": {
+				Description: "The total size of the artifact in a human readable format, e.g. `1.2 GiB`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"layer_media_types": {
+				Description: "The distinct media types of the layers of the artifact, e.g. to assert it only contains expected types of content.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"has_config": {
+				Description: "Whether the manifest has a config, false for artifacts using the empty config descriptor (`application/vnd.oci.empty.v1+json`).",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"bytes_downloaded": {
+				Description: "The number of bytes fetched from the registry while reading the artifact, excluding content served from the local cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -48,41 +144,104 @@ func dataSourceOrasArtifactFileRead(ctx context.Context, d *schema.ResourceData,
 	opts := meta.(*clients)
 
 	reference := d.Get("name").(string)
-	filename := d.Get("filename").(string)
 
-	repo, err := opts.NewRepository(reference)
+	decrypter, err := expandDecrypter(d.Get("decrypt").([]any))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	src, err := opts.CachedTarget(repo)
+	temp, err := makeTempDir(d.Get("use_ramdisk").(bool))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer os.RemoveAll(temp)
 
-	temp, err := os.MkdirTemp("", "terraform-oras-provider-")
+	dst, result, err := opts.pullFiles(ctx, reference, temp, true, pullOptions{
+		indexAnnotations: expandStringMap(d.Get("index_annotations").(map[string]any)),
+		platform:         expandPlatform(d.Get("platform").([]any)),
+		expectedDigest:   d.Get("expected_digest").(string),
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer os.RemoveAll(temp)
 
-	dst, err := file.New(temp)
+	manifest, err := fetchManifest(ctx, dst, result.desc)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-
-	if _, err := oras.Copy(ctx, src, repo.Reference.Reference, dst, repo.Reference.Reference, oras.DefaultCopyOptions); err != nil {
+	if err := extractLayers(ctx, dst, temp, manifest.Layers); err != nil {
 		return diag.FromErr(err)
 	}
 
-	content, err := os.ReadFile(filepath.Join(temp, filename))
-	if err != nil {
-		return diag.FromErr(err)
+	filename := d.Get("filename").(string)
+	var matches []string
+
+	if glob, ok := d.GetOk("glob"); ok {
+		if matches, err = globFiles(temp, glob.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		maxSize := int64(-1)
+		if v, ok := d.GetOkExists("max_size"); ok {
+			maxSize = int64(v.(int))
+		}
+		if matches, err = filterBySize(temp, matches, int64(d.Get("min_size").(int)), maxSize); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if len(matches) == 0 && !d.Get("allow_no_match").(bool) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("No file of %s matches the glob '%s'", reference, glob),
+				Detail:   "Set allow_no_match to read the artifact regardless.",
+			}}
+		}
+
+		if d.Get("single").(bool) {
+			if len(matches) != 1 {
+				return diag.Errorf("glob '%s' matched %d files, expected exactly one", glob, len(matches))
+			}
+			filename, matches = matches[0], nil
+		}
 	}
 
-	// Set the content both as UTF-8 string, and as base64 encoded string
-	_ = d.Set("content", string(content))
-	_ = d.Set("content_base64", base64.StdEncoding.EncodeToString(content))
+	var content []byte
+
+	if matches != nil {
+		files := make(map[string]string, len(matches))
+		filesBase64 := make(map[string]string, len(matches))
+		checksum := sha1.New()
+		for _, match := range matches {
+			data, err := readFile(temp, match, decrypter)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			files[match] = string(data)
+			filesBase64[match] = base64.StdEncoding.EncodeToString(data)
+			checksum.Write([]byte(match))
+			checksum.Write(data)
+		}
+		content = checksum.Sum(nil)
+
+		_ = d.Set("files", files)
+		_ = d.Set("files_base64", filesBase64)
+	} else {
+		if content, err = readFile(temp, filename, decrypter); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Set the content both as UTF-8 string, and as base64 encoded string
+		_ = d.Set("content", string(content))
+		_ = d.Set("content_base64", base64.StdEncoding.EncodeToString(content))
+	}
+
+	_ = d.Set("matched", matches == nil || len(matches) > 0)
+	size := artifactSize(result.desc, manifest)
+	_ = d.Set("size", size)
+	_ = d.Set("size_human", humanize.IBytes(uint64(size)))
+	_ = d.Set("layer_media_types", layerMediaTypes(manifest.Layers))
+	_ = d.Set("has_config", hasConfig(&manifest.Config))
+	_ = d.Set("bytes_downloaded", result.bytesDownloaded)
 
 	// Use the hexadecimal encoding of the checksum of the file content as ID
 	checksum := sha1.Sum(content)
@@ -90,3 +249,73 @@ func dataSourceOrasArtifactFileRead(ctx context.Context, d *schema.ResourceData,
 
 	return nil
 }
+
+// readFile reads the file name from dir, decrypting it when decrypter is set.
+func readFile(dir, name string, decrypter *decrypter) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil || decrypter == nil {
+		return data, err
+	}
+	return decrypter.decrypt(name, data)
+}
+
+// makeTempDir creates the temporary directory to extract an artifact into,
+// preferring a tmpfs-backed directory when useRamdisk is set.
+func makeTempDir(useRamdisk bool) (string, error) {
+	if useRamdisk {
+		if dir := ramdiskDir(); dir != "" {
+			if temp, err := os.MkdirTemp(dir, "terraform-oras-provider-"); err == nil {
+				return temp, nil
+			}
+		}
+	}
+	return os.MkdirTemp("", "terraform-oras-provider-")
+}
+
+// globFiles returns the paths, relative to dir and using forward slashes, of
+// the files in dir matching pattern.
+func globFiles(dir, pattern string) ([]string, error) {
+	matches := []string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ok, _ := path.Match(pattern, rel); ok {
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// filterBySize returns the files of names in dir with a size between min and
+// max bytes, without maximum when max is negative.
+func filterBySize(dir string, names []string, min, max int64) ([]string, error) {
+	if min <= 0 && max < 0 {
+		return names, nil
+	}
+
+	filtered := []string{}
+	for _, name := range names {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if fi.Size() >= min && (max < 0 || fi.Size() <= max) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
+
+func validateGlob(v any, k string) (warnings []string, errs []error) {
+	if _, err := path.Match(v.(string), ""); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid glob pattern: %v", k, err))
+	}
+	return
+}
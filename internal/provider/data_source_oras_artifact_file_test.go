@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterBySize(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"empty.txt", "small.yaml", "large.bin"}
+	for i, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("x", i*10)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		min, max int64
+		want     []string
+	}{
+		{name: "no range", min: 0, max: -1, want: names},
+		{name: "min", min: 1, max: -1, want: []string{"small.yaml", "large.bin"}},
+		{name: "max", min: 0, max: 10, want: []string{"empty.txt", "small.yaml"}},
+		{name: "zero max", min: 0, max: 0, want: []string{"empty.txt"}},
+		{name: "min and max", min: 5, max: 15, want: []string{"small.yaml"}},
+		{name: "none", min: 100, max: -1, want: []string{}},
+	}
+	for _, tt := range tests {
+		got, err := filterBySize(dir, names, tt.min, tt.max)
+		if err != nil {
+			t.Fatalf("filterBySize(%s) error = %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterBySize(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
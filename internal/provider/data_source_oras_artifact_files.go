@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceOrasArtifactFiles() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads multiple files from a remote OCI artifact, pulling the artifact only once.",
+
+		ReadContext: dataSourceOrasArtifactFilesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"filenames": {
+				Description: "The names of the files to read.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"index_annotations": {
+				Description: "When the artifact is an index, select the first manifest of the index having all these annotations.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"use_ramdisk": {
+				Description: "Extract the artifact into a tmpfs-backed temporary directory, e.g. `/dev/shm`. " +
+					"Only supported on Linux, falls back to the regular temporary directory when no tmpfs is available.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"decrypt": decryptSchema(),
+			"files": {
+				Description: "Raw content of the files, as UTF-8 encoded strings keyed by file name.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"files_base64": {
+				Description: "Base64 encoded content of the files, keyed by file name.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"size": {
+				Description: "The total size in bytes of the artifact in the registry, i.e. of its manifest, config and layers.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"size_human": {
+				Description: "The total size of the artifact in a human readable format, e.g. `1.2 GiB`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"bytes_downloaded": {
+				Description: "The number of bytes fetched from the registry while reading the artifact, excluding content served from the local cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasArtifactFilesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("name").(string)
+
+	decrypter, err := expandDecrypter(d.Get("decrypt").([]any))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	temp, err := makeTempDir(d.Get("use_ramdisk").(bool))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer os.RemoveAll(temp)
+
+	dst, result, err := opts.pullFiles(ctx, reference, temp, true, pullOptions{
+		indexAnnotations: expandStringMap(d.Get("index_annotations").(map[string]any)),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	manifest, err := fetchManifest(ctx, dst, result.desc)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := extractLayers(ctx, dst, temp, manifest.Layers); err != nil {
+		return diag.FromErr(err)
+	}
+
+	filenames := expandStringList(d.Get("filenames").([]any))
+	files := make(map[string]string, len(filenames))
+	filesBase64 := make(map[string]string, len(filenames))
+	for _, filename := range filenames {
+		data, err := readFile(temp, filename, decrypter)
+		if errors.Is(err, fs.ErrNotExist) {
+			return diag.Errorf("file '%s' not found in %s", filename, reference)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		files[filename] = string(data)
+		filesBase64[filename] = base64.StdEncoding.EncodeToString(data)
+	}
+
+	_ = d.Set("files", files)
+	_ = d.Set("files_base64", filesBase64)
+	size := artifactSize(result.desc, manifest)
+	_ = d.Set("size", size)
+	_ = d.Set("size_human", humanize.IBytes(uint64(size)))
+	_ = d.Set("bytes_downloaded", result.bytesDownloaded)
+
+	d.SetId(result.desc.Digest.String())
+
+	return nil
+}
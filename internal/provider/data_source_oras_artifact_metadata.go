@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+func dataSourceOrasArtifactMetadata() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads a summary of a remote OCI artifact from its manifest, config and referrers, without downloading any layer.",
+
+		ReadContext: dataSourceOrasArtifactMetadataRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"digest": {
+				Description: "The digest of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"media_type": {
+				Description: "The media type of the manifest, as declared in the manifest or returned by the registry.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"artifact_type": {
+				Description: "The artifact type of the manifest, or the media type of its config for image manifests without artifact type.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"annotations": {
+				Description: "The annotations of the manifest.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"config_media_type": {
+				Description: "The media type of the config of the manifest, empty for manifests without config.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"os": {
+				Description: "The operating system of the image, read from its config. Not set for artifacts which are not images.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"architecture": {
+				Description: "The CPU architecture of the image, read from its config. Not set for artifacts which are not images.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"layer_count": {
+				Description: "The number of layers, or blobs, of the manifest.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"layers_size": {
+				Description: "The total size in bytes of the layers of the manifest.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"layer_media_types": {
+				Description: "The distinct media types of the layers of the artifact.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"referrer_count": {
+				Description: "The number of referrers of the artifact, e.g. its signatures, SBOMs and attestations.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasArtifactMetadataRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, rc, err := repo.FetchReference(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+	data, err := content.ReadAll(rc, desc)
+	rc.Close()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var manifest struct {
+		MediaType    string               `json:"mediaType"`
+		ArtifactType string               `json:"artifactType"`
+		Config       *ocispec.Descriptor  `json:"config"`
+		Layers       []ocispec.Descriptor `json:"layers"`
+		Blobs        []ocispec.Descriptor `json:"blobs"`
+		Annotations  map[string]string    `json:"annotations"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return diag.Errorf("failed to parse manifest of %s: %s", reference, err)
+	}
+
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = desc.MediaType
+	}
+
+	var configMediaType string
+	if manifest.Config != nil {
+		configMediaType = manifest.Config.MediaType
+	}
+	artifactType := manifest.ArtifactType
+	if artifactType == "" {
+		artifactType = configMediaType
+	}
+
+	// only the config of images is fetched, other configs may be arbitrarily large
+	if configMediaType == ocispec.MediaTypeImageConfig || configMediaType == mediaTypeDockerImageConfig {
+		config, err := fetchImageConfig(ctx, repo.Blobs(), *manifest.Config)
+		if err != nil {
+			return diag.Errorf("failed to read config of %s: %s", reference, err)
+		}
+		_ = d.Set("os", config.OS)
+		_ = d.Set("architecture", config.Architecture)
+	}
+
+	layers := append(manifest.Layers, manifest.Blobs...)
+	var layersSize int64
+	for _, layer := range layers {
+		layersSize += layer.Size
+	}
+
+	referrerCount := 0
+	err = repo.Referrers(ctx, desc, "", func(referrers []ocispec.Descriptor) error {
+		referrerCount += len(referrers)
+		return nil
+	})
+	if err != nil {
+		return diag.Errorf("failed to list referrers of %s: %s", reference, err)
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+	_ = d.Set("media_type", mediaType)
+	_ = d.Set("artifact_type", artifactType)
+	_ = d.Set("annotations", manifest.Annotations)
+	_ = d.Set("config_media_type", configMediaType)
+	_ = d.Set("layer_count", len(layers))
+	_ = d.Set("layers_size", layersSize)
+	_ = d.Set("layer_media_types", layerMediaTypes(layers))
+	_ = d.Set("referrer_count", referrerCount)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasArtifactMetadataRead(t *testing.T) {
+	config, _ := json.Marshal(ocispec.Image{Platform: ocispec.Platform{OS: "linux", Architecture: "amd64"}})
+	configDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromBytes(config), Size: int64(len(config))}
+	layers := []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer1"), Size: 100},
+		{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer2"), Size: 50},
+	}
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      configDesc,
+		Layers:      layers,
+		Annotations: map[string]string{"key": "value"},
+	})
+	manifestDigest := digest.FromBytes(manifest)
+	referrers, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromString("signature"), Size: 10, ArtifactType: "application/vnd.test.signature"},
+		},
+	})
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			_, _ = w.Write(manifest)
+		case "/v2/app/blobs/" + configDesc.Digest.String():
+			_, _ = w.Write(config)
+		case "/v2/app/referrers/" + manifestDigest.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			_, _ = w.Write(referrers)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	d := schema.TestResourceDataRaw(t, dataSourceOrasArtifactMetadata().Schema, map[string]any{
+		"reference": u.Host + "/app:v1",
+	})
+	if diags := dataSourceOrasArtifactMetadataRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("dataSourceOrasArtifactMetadataRead() = %v", diags)
+	}
+
+	if d.Id() != manifestDigest.String() {
+		t.Errorf("id = %s, want %s", d.Id(), manifestDigest)
+	}
+	want := map[string]any{
+		"artifact_type":   ocispec.MediaTypeImageConfig,
+		"os":              "linux",
+		"architecture":    "amd64",
+		"layer_count":     2,
+		"layers_size":     150,
+		"referrer_count":  1,
+		"annotations.key": "value",
+	}
+	for key, value := range want {
+		if got := d.Get(key); got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func dataSourceOrasBlobExists() *schema.Resource {
+	return &schema.Resource{
+		Description: "Checks whether a blob exists in a remote repository, without downloading it.",
+
+		ReadContext: dataSourceOrasBlobExistsRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Description: "The repository to look for the blob, without any tag or digest, e.g. `ghcr.io/org/app`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"digest": {
+				Description:  "The digest of the blob, e.g. `sha256:...`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateDigest,
+			},
+			"exists": {
+				Description: "Whether the blob exists in the repository.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasBlobExistsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repository := d.Get("repository").(string)
+	dgst := digest.Digest(d.Get("digest").(string))
+
+	repo, err := opts.newReadRepository(repository)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// not found is reported as false, any other error is returned
+	exists, err := repo.Blobs().Exists(ctx, ocispec.Descriptor{Digest: dgst})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("exists", exists)
+
+	d.SetId(fmt.Sprintf("%s:%t", dgst, exists))
+
+	return nil
+}
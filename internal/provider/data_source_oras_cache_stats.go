@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceOrasCacheStats() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reports how effective the local OCI cache (`cache_dir` or `ORAS_CACHE`) was for the artifacts pulled so far by this provider instance. " +
+			"Use `depends_on` to read the statistics after the data sources pulling the artifacts.",
+
+		ReadContext: dataSourceOrasCacheStatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"cache_hits": {
+				Description: "The number of blobs and manifests served from the cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"cache_misses": {
+				Description: "The number of blobs and manifests fetched from a registry because they were not cached.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"hit_ratio": {
+				Description: "The ratio of cache hits to all cache lookups, between `0` and `1`. `0` when nothing was pulled through the cache.",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasCacheStatsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	hits, misses := opts.cacheCounters.get()
+
+	var ratio float64
+	if total := hits + misses; total > 0 {
+		ratio = float64(hits) / float64(total)
+	}
+
+	_ = d.Set("cache_hits", hits)
+	_ = d.Set("cache_misses", misses)
+	_ = d.Set("hit_ratio", ratio)
+
+	d.SetId(fmt.Sprintf("%d:%d", hits, misses))
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// errCatalogLimit stops the listing of the repositories once the limit is
+// reached.
+var errCatalogLimit = errors.New("limit reached")
+
+func dataSourceOrasCatalog() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the repositories of a registry, using its catalog endpoint. " +
+			"Many public registries, such as Docker Hub and GitHub, restrict or disable this endpoint.",
+
+		ReadContext: dataSourceOrasCatalogRead,
+
+		Schema: map[string]*schema.Schema{
+			"registry": {
+				Description: "The host of the registry, e.g. `registry.example.com`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"last": {
+				Description: "Only list the repositories after this repository, in the order of the registry, typically lexical.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"limit": {
+				Description:  "The maximum number of repositories to list. By default all repositories are listed, following the pagination of the registry.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"repositories": {
+				Description: "The repositories of the registry, in the order returned by the registry.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceOrasCatalogRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	host := d.Get("registry").(string)
+	reg, err := opts.NewRegistry(host)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	limit := d.Get("limit").(int)
+	if limit > 0 {
+		reg.RepositoryListPageSize = limit
+	}
+
+	repositories := []string{}
+	err = reg.Repositories(ctx, d.Get("last").(string), func(page []string) error {
+		repositories = append(repositories, page...)
+		if limit > 0 && len(repositories) >= limit {
+			repositories = repositories[:limit]
+			return errCatalogLimit
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errCatalogLimit) {
+		var errResp *errcode.ErrorResponse
+		if errors.As(err, &errResp) {
+			switch errResp.StatusCode {
+			case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed:
+				return diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Registry %s does not allow listing its repositories", host),
+					Detail: fmt.Sprintf("The catalog endpoint is restricted or disabled by the registry: %v. "+
+						"Check that the credentials of the registry grant access to the catalog, or list the tags of known repositories instead.", err),
+				}}
+			}
+		}
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("repositories", repositories)
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(host+"\n"+strings.Join(repositories, "\n")))))
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasCatalogRead(t *testing.T) {
+	pages := map[string][]string{
+		"":      {"org/a", "org/b"},
+		"org/b": {"org/c"},
+	}
+	disabled := false
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if disabled {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		last := r.URL.Query().Get("last")
+		if last == "" {
+			w.Header().Set("Link", `</v2/_catalog?last=org/b>; rel="next"`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"repositories": pages[last]})
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []any
+	}{
+		{name: "all", want: []any{"org/a", "org/b", "org/c"}},
+		{name: "limit", limit: 1, want: []any{"org/a"}},
+	}
+	for _, tt := range tests {
+		d := schema.TestResourceDataRaw(t, dataSourceOrasCatalog().Schema, map[string]any{"registry": u.Host, "limit": tt.limit})
+		if diags := dataSourceOrasCatalogRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("dataSourceOrasCatalogRead(%s) = %v", tt.name, diags)
+		}
+		if got := d.Get("repositories"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("dataSourceOrasCatalogRead(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	disabled = true
+	d := schema.TestResourceDataRaw(t, dataSourceOrasCatalog().Schema, map[string]any{"registry": u.Host})
+	diags := dataSourceOrasCatalogRead(context.Background(), d, c)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "does not allow listing") {
+		t.Errorf("dataSourceOrasCatalogRead(disabled) = %v, want catalog diagnostic", diags)
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"oras.land/oras-go/v2/registry"
+)
+
+func dataSourceOrasChannel() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resolves a release channel tag (e.g. `stable` or `edge`) of a repository to the immutable digest it currently points to.",
+
+		ReadContext: dataSourceOrasChannelRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Description: "The repository of the artifact, without any tag or digest, e.g. `ghcr.io/org/app`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"channel": {
+				Description:  "The channel tag to resolve.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"require_signature_referrer": {
+				Description: "Fail when no referrer with the artifact type of a Notary or Cosign signature is attached to the resolved digest. " +
+					"Only the presence of such a referrer is checked, the signature is not verified, use `oras_referrers` with `signer_identity` to check the signer.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"digest": {
+				Description: "The digest the channel resolved to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"pinned_reference": {
+				Description: "The reference of the artifact pinned to the resolved digest, e.g. `ghcr.io/org/app@sha256:...`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"has_signature_referrer": {
+				Description: "Whether a referrer with the artifact type of a signature is attached to the resolved digest.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasChannelRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repository := d.Get("repository").(string)
+	channel := d.Get("channel").(string)
+
+	repo, err := opts.newReadRepository(repository)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, channel)
+	if err != nil {
+		return diag.Errorf("failed to resolve channel '%s' of %s: %s", channel, repository, err)
+	}
+
+	signed, err := hasSignature(ctx, repo, desc)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !signed && d.Get("require_signature_referrer").(bool) {
+		return diag.Errorf("channel '%s' of %s resolved to %s, which has no signature referrer attached", channel, repository, desc.Digest)
+	}
+	_ = d.Set("has_signature_referrer", signed)
+
+	_ = d.Set("digest", desc.Digest.String())
+	// pinned to the registry of the artifact rather than a mirror it is pulled from
+	ref, err := registry.ParseReference(opts.expandReference(repository))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ref.Reference = desc.Digest.String()
+	_ = d.Set("pinned_reference", ref.String())
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasChannelRead_mirror(t *testing.T) {
+	dgst := digest.FromString("stable")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/cache/org/app/manifests/stable" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+		w.Header().Set("Content-Length", "100")
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{
+		client:  &auth.Client{Client: srv.Client()},
+		mirrors: []registryMirror{{registry: "registry.example.com", endpoint: u.Host, pathPrefix: "cache"}},
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceOrasChannel().Schema, map[string]any{
+		"repository": "registry.example.com/org/app",
+		"channel":    "stable",
+	})
+	if diags := dataSourceOrasChannelRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("dataSourceOrasChannelRead() = %v", diags)
+	}
+
+	want := "registry.example.com/org/app@" + dgst.String()
+	if got := d.Get("pinned_reference").(string); got != want {
+		t.Errorf("pinned_reference = %s, want %s", got, want)
+	}
+	if d.Get("has_signature_referrer").(bool) {
+		t.Error("has_signature_referrer = true, want false without signature referrers")
+	}
+}
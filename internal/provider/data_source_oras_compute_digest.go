@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func dataSourceOrasComputeDigest() *schema.Resource {
+	return &schema.Resource{
+		Description: "Computes the digest of the manifest `oras_push` would push for the given files, without any network access. " +
+			"Use it to know the digest of an artifact ahead of time, or to detect pushes that would not change anything.",
+
+		ReadContext: dataSourceOrasComputeDigestRead,
+
+		Schema: map[string]*schema.Schema{
+			"files": {
+				Description: "The files of the artifact, either read from `path` or given as inline `content`.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Description: "The path of the file.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"content": {
+							Description: "The content of the file, instead of reading it from `path`.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"name": {
+							Description: "The name of the file, used as title of the layer. Defaults to the base name of `path`, required with `content`.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"media_type": {
+							Description: "The media type of the layer.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     ocispec.MediaTypeImageLayer,
+						},
+					},
+				},
+			},
+			"artifact_type": {
+				Description: "The artifact type of the manifest. Defaults to `application/vnd.unknown.artifact.v1` without `config`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"config": packConfigSchema(false),
+			"annotations": {
+				Description: "The annotations of the manifest.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"digest": {
+				Description: "The digest of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasComputeDigestRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var layers []ocispec.Descriptor
+	names := make(map[string]bool)
+	for i, f := range d.Get("files").([]any) {
+		m := f.(map[string]any)
+		layer, err := computeLayer(m["path"].(string), m["content"].(string), m["name"].(string), m["media_type"].(string))
+		if err != nil {
+			return diag.Errorf("files.%d: %s", i, err)
+		}
+
+		name := layer.Annotations[ocispec.AnnotationTitle]
+		if names[name] {
+			return diag.Errorf("files.%d: duplicate name %s", i, name)
+		}
+		names[name] = true
+
+		layers = append(layers, layer)
+	}
+
+	config, err := expandPackConfig(d.Get("config").([]any))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := packArtifact(ctx, memory.New(), d.Get("artifact_type").(string), config, layers, expandStringMap(d.Get("annotations").(map[string]any)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
+
+// computeLayer returns the descriptor of a file as added to a file store by
+// oras_push.
+func computeLayer(path, data, name, mediaType string) (ocispec.Descriptor, error) {
+	var blob []byte
+	switch {
+	case path != "" && data != "":
+		return ocispec.Descriptor{}, errors.New("only one of path and content can be set")
+	case path != "":
+		var err error
+		if blob, err = os.ReadFile(path); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if name == "" {
+			name = filepath.Base(path)
+		}
+	default:
+		if name == "" {
+			return ocispec.Descriptor{}, errors.New("name must be set with content")
+		}
+		blob = []byte(data)
+	}
+
+	desc := content.NewDescriptorFromBytes(mediaType, blob)
+	desc.Annotations = map[string]string{ocispec.AnnotationTitle: name}
+	return desc, nil
+}
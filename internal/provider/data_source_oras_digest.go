@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/errdef"
+)
+
+func dataSourceOrasDigest() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resolves a reference to the digest of its manifest, without downloading the artifact, e.g. to pin a deployment to the current digest of a tag.",
+
+		ReadContext: dataSourceOrasDigestRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference to resolve, by tag or by digest, e.g. `ghcr.io/org/app:v1.0.0`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"digest": {
+				Description: "The digest of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"media_type": {
+				Description: "The media type of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"size": {
+				Description: "The size of the manifest in bytes.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasDigestRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if repo.Reference.Reference == "" {
+		return diag.Errorf("reference %s must include a tag or a digest", reference)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return diag.Errorf("reference %s does not exist: %s", reference, err)
+	}
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+	_ = d.Set("media_type", desc.MediaType)
+	_ = d.Set("size", desc.Size)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasDigestRead(t *testing.T) {
+	dgst := digest.FromString("manifest")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1", "/v2/app/manifests/" + dgst.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+			w.Header().Set("Content-Length", "8")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	for _, reference := range []string{"app:v1", "app@" + dgst.String()} {
+		d := schema.TestResourceDataRaw(t, dataSourceOrasDigest().Schema, map[string]any{"reference": u.Host + "/" + reference})
+		if diags := dataSourceOrasDigestRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("dataSourceOrasDigestRead(%s) = %v", reference, diags)
+		}
+		if d.Id() != dgst.String() || d.Get("media_type") != ocispec.MediaTypeImageManifest || d.Get("size") != 8 {
+			t.Errorf("dataSourceOrasDigestRead(%s) = %s, %v, %v", reference, d.Id(), d.Get("media_type"), d.Get("size"))
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceOrasDigest().Schema, map[string]any{"reference": u.Host + "/app:v2"})
+	diags := dataSourceOrasDigestRead(context.Background(), d, c)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "does not exist") {
+		t.Errorf("dataSourceOrasDigestRead(v2) = %v, want not exist error", diags)
+	}
+}
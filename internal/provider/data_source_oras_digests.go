@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceOrasDigests() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resolves many references to their digests at once, without downloading the artifacts.",
+
+		ReadContext: dataSourceOrasDigestsRead,
+
+		Schema: map[string]*schema.Schema{
+			"references": {
+				Description: "The references of the remote artifacts to resolve.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"concurrency": {
+				Description:  "The maximum number of references resolved concurrently. Defaults to `4`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"digests": {
+				Description: "The resolved digests, keyed by reference.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceOrasDigestsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	var references []string
+	for _, reference := range d.Get("references").(*schema.Set).List() {
+		references = append(references, reference.(string))
+	}
+	sort.Strings(references)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		diags   diag.Diagnostics
+		digests = make(map[string]string, len(references))
+		sem     = make(chan struct{}, d.Get("concurrency").(int))
+	)
+
+	for _, reference := range references {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(reference string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			dgst, err := opts.resolve(ctx, reference)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "failed to resolve " + reference,
+					Detail:   err.Error(),
+				})
+				return
+			}
+			digests[reference] = dgst
+		}(reference)
+	}
+	wg.Wait()
+
+	if diags.HasError() {
+		return diags
+	}
+
+	_ = d.Set("digests", digests)
+
+	checksum := sha1.New()
+	for _, reference := range references {
+		checksum.Write([]byte(reference + "=" + digests[reference] + "\n"))
+	}
+	d.SetId(hex.EncodeToString(checksum.Sum(nil)))
+
+	return nil
+}
+
+func (c *clients) resolve(ctx context.Context, reference string) (string, error) {
+	repo, err := c.newReadRepository(reference)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return "", explainResolveError(ctx, repo, err)
+	}
+	return desc.Digest.String(), nil
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// manifestAcceptHeader is the Accept header sent when requesting a manifest.
+var manifestAcceptHeader = strings.Join([]string{
+	ocispec.MediaTypeImageManifest,
+	ocispec.MediaTypeImageIndex,
+	mediaTypeDockerManifest,
+	mediaTypeDockerManifestList,
+}, ", ")
+
+func dataSourceOrasLastPushed() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reports when the manifest of a tag was last pushed, for retention and staleness checks. " +
+			"Uses the `Last-Modified` header returned by the registry when available, and falls back to the `created` time of the image config otherwise.",
+
+		ReadContext: dataSourceOrasLastPushedRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"last_pushed": {
+				Description: "The time the manifest was last pushed, in RFC 3339 format. Empty when neither the registry nor the image config provide it.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"source": {
+				Description: "Where `last_pushed` was read from: `registry` for the `Last-Modified` header, `config` for the image config, or empty.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasLastPushedRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	var (
+		lastPushed string
+		source     string
+	)
+	if t, ok, err := manifestLastModified(ctx, repo); err != nil {
+		return diag.FromErr(err)
+	} else if ok {
+		lastPushed, source = t.UTC().Format(time.RFC3339), "registry"
+	} else if t, err := imageCreated(ctx, repo, desc); err != nil {
+		return diag.FromErr(err)
+	} else if t != nil {
+		lastPushed, source = t.UTC().Format(time.RFC3339), "config"
+	}
+
+	_ = d.Set("last_pushed", lastPushed)
+	_ = d.Set("source", source)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
+
+// manifestLastModified returns the Last-Modified header the registry returns
+// for the manifest of the reference of repo, if any.
+func manifestLastModified(ctx context.Context, repo *remote.Repository) (time.Time, bool, error) {
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, repo.Reference.Host(), repo.Reference.Repository, repo.Reference.Reference)
+
+	ctx = auth.AppendScopes(ctx, auth.ScopeRepository(repo.Reference.Repository, auth.ActionPull))
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	req.Header.Set("Accept", manifestAcceptHeader)
+
+	resp, err := repo.Client.Do(req)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, false, nil
+	}
+	t, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+	return t, true, nil
+}
+
+// imageCreated returns the created time of the image config of the manifest
+// described by desc, or nil when it is not an image or has no created time.
+func imageCreated(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (*time.Time, error) {
+	if desc.MediaType != ocispec.MediaTypeImageManifest && desc.MediaType != mediaTypeDockerManifest {
+		return nil, nil
+	}
+
+	manifest, err := fetchManifest(ctx, fetcher, desc)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Config.MediaType != ocispec.MediaTypeImageConfig && manifest.Config.MediaType != mediaTypeDockerImageConfig {
+		return nil, nil
+	}
+
+	config, err := fetchImageConfig(ctx, fetcher, manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+	return config.Created, nil
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestManifestLastModified(t *testing.T) {
+	lastModified := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1":
+			w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		case "/v2/app/manifests/v2":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	for tag, want := range map[string]bool{"v1": true, "v2": false, "v3": false} {
+		repo, err := c.NewRepository(u.Host + "/app:" + tag)
+		if err != nil {
+			t.Fatal("NewRepository() error =", err)
+		}
+		got, ok, err := manifestLastModified(context.Background(), repo)
+		if err != nil {
+			t.Fatal("manifestLastModified() error =", err)
+		}
+		if ok != want || (ok && !got.Equal(lastModified)) {
+			t.Errorf("manifestLastModified(%s) = %v, %v, want %v", tag, got, ok, want)
+		}
+	}
+}
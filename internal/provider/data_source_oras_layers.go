@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// largeLayersSize is the total layer size above which a warning is emitted,
+// as the content of the layers ends up in the state.
+const largeLayersSize = 4 * 1024 * 1024
+
+func dataSourceOrasLayers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches the raw layer blobs of a remote artifact, for artifacts where each layer is consumed separately. " +
+			"The layers are not extracted as files, use `oras_artifact` or `oras_artifact_file` for that.",
+
+		ReadContext: dataSourceOrasLayersRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"max_total_size": {
+				Description:  "The maximum total size in bytes of the layers, larger artifacts are rejected before fetching any layer. Defaults to `16777216` (16 MiB).",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      16 * 1024 * 1024,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"concurrency": {
+				Description:  "The maximum number of layers fetched concurrently. Defaults to `4`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"layers": {
+				Description: "The layers of the artifact, in manifest order.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"media_type": {
+							Description: "The media type of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"size": {
+							Description: "The size in bytes of the layer.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"digest": {
+							Description: "The digest of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"content_base64": {
+							Description: "Base64 encoded content of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOrasLayersRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	desc, manifestLayers, contents, diags := fetchLayers(ctx, meta.(*clients), d.Get("reference").(string),
+		int64(d.Get("max_total_size").(int)), d.Get("concurrency").(int))
+	if diags.HasError() {
+		return diags
+	}
+
+	layers := make([]any, len(manifestLayers))
+	for i, layer := range manifestLayers {
+		layers[i] = map[string]any{
+			"media_type":     layer.MediaType,
+			"size":           layer.Size,
+			"digest":         layer.Digest.String(),
+			"content_base64": base64.StdEncoding.EncodeToString(contents[i]),
+		}
+	}
+
+	_ = d.Set("layers", layers)
+
+	d.SetId(desc.Digest.String())
+
+	return diags
+}
+
+// fetchLayers fetches the content of the layers of the manifest referenced by
+// reference, at most concurrency layers at a time. Artifacts with layers
+// larger than maxTotalSize in total are rejected before fetching any layer.
+func fetchLayers(ctx context.Context, opts *clients, reference string, maxTotalSize int64, concurrency int) (ocispec.Descriptor, []ocispec.Descriptor, [][]byte, diag.Diagnostics) {
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest && desc.MediaType != mediaTypeDockerManifest {
+		return ocispec.Descriptor{}, nil, nil, diag.Errorf("%s is not a manifest but %s", reference, desc.MediaType)
+	}
+
+	manifest, err := fetchManifest(ctx, repo, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, diag.FromErr(err)
+	}
+
+	var total int64
+	for _, layer := range manifest.Layers {
+		total += layer.Size
+	}
+	if total > maxTotalSize {
+		return ocispec.Descriptor{}, nil, nil, diag.Errorf("the layers of %s have a total size of %s, exceeding the max_total_size of %s",
+			reference, humanize.IBytes(uint64(total)), humanize.IBytes(uint64(maxTotalSize)))
+	}
+
+	var diags diag.Diagnostics
+	if total > largeLayersSize {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Large layers read from %s", reference),
+			Detail: fmt.Sprintf("The layers have a total size of %s, their content is stored in the Terraform state.",
+				humanize.IBytes(uint64(total))),
+		})
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		contents = make([][]byte, len(manifest.Layers))
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	for i, layer := range manifest.Layers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, layer ocispec.Descriptor) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			data, err := content.FetchAll(ctx, repo.Blobs(), layer)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "failed to fetch layer " + layer.Digest.String(),
+					Detail:   err.Error(),
+				})
+				return
+			}
+			contents[i] = data
+		}(i, layer)
+	}
+	wg.Wait()
+
+	return desc, manifest.Layers, contents, diags
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func dataSourceOrasLayersByTitle() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches the layer blobs of a remote artifact keyed by their `org.opencontainers.image.title` annotation, " +
+			"i.e. the file names of artifacts pushed with `oras push`. Layers without title are keyed by their digest.",
+
+		ReadContext: dataSourceOrasLayersByTitleRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"max_total_size": {
+				Description:  "The maximum total size in bytes of the layers, larger artifacts are rejected before fetching any layer. Defaults to `16777216` (16 MiB).",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      16 * 1024 * 1024,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"concurrency": {
+				Description:  "The maximum number of layers fetched concurrently. Defaults to `4`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"contents": {
+				Description: "The content of the layers which are valid UTF-8 text.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"contents_base64": {
+				Description: "Base64 encoded content of all layers.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"media_types": {
+				Description: "The media type of the layers.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"digests": {
+				Description: "The digest of the layers.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"sizes": {
+				Description: "The size in bytes of the layers.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func dataSourceOrasLayersByTitleRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	reference := d.Get("reference").(string)
+
+	desc, layers, data, diags := fetchLayers(ctx, meta.(*clients), reference,
+		int64(d.Get("max_total_size").(int)), d.Get("concurrency").(int))
+	if diags.HasError() {
+		return diags
+	}
+
+	var (
+		contents       = map[string]any{}
+		contentsBase64 = map[string]any{}
+		mediaTypes     = map[string]any{}
+		digests        = map[string]any{}
+		sizes          = map[string]any{}
+	)
+	for i, layer := range layers {
+		key := layerKey(layer)
+		if _, ok := digests[key]; ok {
+			return append(diags, diag.Errorf("%s has multiple layers with title '%s'", reference, key)...)
+		}
+		if utf8.Valid(data[i]) {
+			contents[key] = string(data[i])
+		}
+		contentsBase64[key] = base64.StdEncoding.EncodeToString(data[i])
+		mediaTypes[key] = layer.MediaType
+		digests[key] = layer.Digest.String()
+		sizes[key] = int(layer.Size)
+	}
+
+	_ = d.Set("contents", contents)
+	_ = d.Set("contents_base64", contentsBase64)
+	_ = d.Set("media_types", mediaTypes)
+	_ = d.Set("digests", digests)
+	_ = d.Set("sizes", sizes)
+
+	d.SetId(desc.Digest.String())
+
+	return diags
+}
+
+// layerKey returns the title of a layer, or its digest when it has none.
+func layerKey(layer ocispec.Descriptor) string {
+	if title := layer.Annotations[ocispec.AnnotationTitle]; title != "" {
+		return title
+	}
+	return layer.Digest.String()
+}
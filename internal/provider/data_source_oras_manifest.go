@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// Media types of the legacy Docker image manifest schema 1, which is not
+// supported by oras-go.
+const (
+	mediaTypeDockerSchema1Manifest       = "application/vnd.docker.distribution.manifest.v1+json"
+	mediaTypeDockerSchema1SignedManifest = "application/vnd.docker.distribution.manifest.v1+prettyjws"
+)
+
+func dataSourceOrasManifest() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the manifest of a remote OCI artifact, without downloading its content.",
+
+		ReadContext: dataSourceOrasManifestRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"referrer_artifact_type": {
+				Description: "Read the manifest of the referrer of `reference` with this artifact type instead, e.g. `application/vnd.cncf.notary.signature`. " +
+					"When multiple referrers have the artifact type, the most recently created one is selected, based on their `org.opencontainers.image.created` annotation.",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"digest": {
+				Description: "The digest of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"manifest_json": {
+				Description: "The raw content of the manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"layers": {
+				Description: "The layers, or blobs, of the manifest.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"media_type": {
+							Description: "The media type of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"digest": {
+							Description: "The digest of the layer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"size": {
+							Description: "The size in bytes of the layer.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"annotations": {
+							Description: "The annotations of the layer.",
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"layer_media_types": {
+				Description: "The distinct media types of the layers of the artifact, e.g. to assert it only contains expected types of content.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"has_config": {
+				Description: "Whether the manifest has a config, false for artifacts using the empty config descriptor (`application/vnd.oci.empty.v1+json`).",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"schema_version": {
+				Description: "The `schemaVersion` of the manifest.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"media_type": {
+				Description: "The media type of the manifest, as declared in the manifest or returned by the registry.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"artifact_type": {
+				Description: "The artifact type of the manifest, or the media type of its config for image manifests without artifact type.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"config_media_type": {
+				Description: "The media type of the config of the manifest, empty for manifests without config.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"annotations": {
+				Description: "The annotations of the manifest.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"merge_config_labels": {
+				Description: "Merge the labels of the image config, e.g. set with `LABEL` in a Dockerfile, into `annotations`. " +
+					"The annotations of the manifest take precedence over labels with the same key.",
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasManifestRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, rc, err := repo.FetchReference(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+	data, err := content.ReadAll(rc, desc)
+	rc.Close()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if artifactType, ok := d.GetOk("referrer_artifact_type"); ok {
+		referrer, err := findReferrer(ctx, repo, desc, artifactType.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		referrerRC, err := repo.Fetch(ctx, referrer)
+		if err != nil {
+			return diag.Errorf("failed to fetch referrer %s of %s: %s", referrer.Digest, reference, err)
+		}
+		data, err = content.ReadAll(referrerRC, referrer)
+		referrerRC.Close()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		desc = referrer
+	}
+
+	var manifest struct {
+		SchemaVersion int                  `json:"schemaVersion"`
+		MediaType     string               `json:"mediaType"`
+		ArtifactType  string               `json:"artifactType"`
+		Config        *ocispec.Descriptor  `json:"config"`
+		Layers        []ocispec.Descriptor `json:"layers"`
+		Blobs         []ocispec.Descriptor `json:"blobs"`
+		Annotations   map[string]string    `json:"annotations"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return diag.Errorf("failed to parse manifest of %s: %s", reference, err)
+	}
+
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = desc.MediaType
+	}
+
+	if manifest.SchemaVersion == 1 || mediaType == mediaTypeDockerSchema1Manifest || mediaType == mediaTypeDockerSchema1SignedManifest {
+		return diag.Errorf("the manifest of %s uses the legacy Docker image manifest schema 1, which is not supported; push the artifact again with a recent client to convert it to schema 2 or OCI", reference)
+	}
+
+	var configMediaType string
+	if manifest.Config != nil {
+		configMediaType = manifest.Config.MediaType
+	}
+	artifactType := manifest.ArtifactType
+	if artifactType == "" {
+		artifactType = configMediaType
+	}
+
+	annotations := manifest.Annotations
+	if d.Get("merge_config_labels").(bool) {
+		if annotations, err = mergeConfigLabels(ctx, repo.Blobs(), manifest.Config, annotations); err != nil {
+			return diag.Errorf("failed to read config of %s: %s", reference, err)
+		}
+	}
+
+	var layers []any
+	for _, layer := range append(manifest.Layers, manifest.Blobs...) {
+		layers = append(layers, map[string]any{
+			"media_type":  layer.MediaType,
+			"digest":      layer.Digest.String(),
+			"size":        layer.Size,
+			"annotations": layer.Annotations,
+		})
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+	_ = d.Set("manifest_json", string(data))
+	_ = d.Set("layers", layers)
+	_ = d.Set("layer_media_types", layerMediaTypes(append(manifest.Layers, manifest.Blobs...)))
+	_ = d.Set("has_config", hasConfig(manifest.Config))
+	_ = d.Set("schema_version", manifest.SchemaVersion)
+	_ = d.Set("media_type", mediaType)
+	_ = d.Set("artifact_type", artifactType)
+	_ = d.Set("config_media_type", configMediaType)
+	_ = d.Set("annotations", annotations)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
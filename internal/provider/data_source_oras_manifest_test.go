@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasManifestRead_referrerFetchFails(t *testing.T) {
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.ScratchDescriptor,
+		Layers:    []ocispec.Descriptor{},
+	})
+	subject := digest.FromBytes(manifest)
+	signature := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		Digest:       digest.FromString("signature"),
+		Size:         10,
+		ArtifactType: "application/vnd.cncf.notary.signature",
+	}
+	referrers, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{signature},
+	})
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", subject.String())
+			_, _ = w.Write(manifest)
+		case "/v2/app/referrers/" + subject.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			_, _ = w.Write(referrers)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	d := schema.TestResourceDataRaw(t, dataSourceOrasManifest().Schema, map[string]any{
+		"reference":              u.Host + "/app:v1",
+		"referrer_artifact_type": signature.ArtifactType,
+	})
+	diags := dataSourceOrasManifestRead(context.Background(), d, c)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "failed to fetch referrer") {
+		t.Errorf("dataSourceOrasManifestRead() = %v, want the referrer fetch error", diags)
+	}
+}
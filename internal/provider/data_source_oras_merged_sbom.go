@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceOrasMergedSBOM() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches all SBOMs attached to an artifact as referrers, and merges their components into a single list. " +
+			"Only CycloneDX JSON and XML SBOMs are supported.",
+
+		ReadContext: dataSourceOrasMergedSBOMRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"sbom_count": {
+				Description: "The number of SBOMs that were merged.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"components": {
+				Description: "The components of all SBOMs, without duplicates, sorted by name and version.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Resource{Schema: sbomComponentSchema()},
+			},
+		},
+	}
+}
+
+func dataSourceOrasMergedSBOMRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	subject, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	referrers, err := sbomReferrers(ctx, repo, subject)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var sboms [][]sbomComponent
+	for _, referrer := range referrers {
+		blobs, documents, err := fetchSBOMDocuments(ctx, repo, referrer)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for i, document := range documents {
+			components, err := parseSBOM(blobs[i].MediaType, document)
+			if err != nil {
+				return diag.Errorf("SBOM %s attached to %s: %s", referrer.Digest, reference, err)
+			}
+			sboms = append(sboms, components)
+		}
+	}
+
+	var components []any
+	for _, c := range mergeSBOMComponents(sboms...) {
+		components = append(components, c.toMap())
+	}
+
+	_ = d.Set("sbom_count", len(sboms))
+	_ = d.Set("components", components)
+
+	d.SetId(subject.Digest.String())
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry"
+)
+
+func dataSourceOrasReferenceParse() *schema.Resource {
+	return &schema.Resource{
+		Description: "Parses and validates an artifact reference into its components, without any network access.",
+
+		ReadContext: dataSourceOrasReferenceParseRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference to parse, e.g. `ghcr.io/org/app:v1.0.0` or `ghcr.io/org/app@sha256:...`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"registry": {
+				Description: "The registry of the reference, e.g. `ghcr.io`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"repository": {
+				Description: "The repository of the reference, e.g. `org/app`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"tag": {
+				Description: "The tag of the reference, empty when the reference has no tag.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"digest": {
+				Description: "The digest of the reference, empty when the reference has no digest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"normalized": {
+				Description: "The reference in its normalized form, `registry/repository[:tag][@digest]`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasReferenceParseRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	raw := d.Get("reference").(string)
+
+	ref, err := registry.ParseReference(raw)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var tag, dgst string
+	if _, err := ref.Digest(); err == nil {
+		dgst = ref.Reference
+		// a reference of the form name:tag@digest only keeps the digest
+		rest := strings.TrimPrefix(raw, ref.Registry+"/"+ref.Repository)
+		if before, _, ok := strings.Cut(rest, "@"); ok && strings.HasPrefix(before, ":") {
+			tag = before[1:]
+		}
+	} else {
+		tag = ref.Reference
+	}
+
+	normalized := ref.Registry + "/" + ref.Repository
+	if tag != "" {
+		normalized += ":" + tag
+	}
+	if dgst != "" {
+		normalized += "@" + dgst
+	}
+
+	_ = d.Set("registry", ref.Registry)
+	_ = d.Set("repository", ref.Repository)
+	_ = d.Set("tag", tag)
+	_ = d.Set("digest", dgst)
+	_ = d.Set("normalized", normalized)
+
+	d.SetId(normalized)
+
+	return nil
+}
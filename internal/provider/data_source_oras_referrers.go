@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func dataSourceOrasReferrers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the referrers of an artifact, e.g. its signatures, SBOMs and attestations. " +
+			"On registries without the OCI referrers API, the referrers are read from the index tagged with the digest of the artifact, " +
+			"e.g. `sha256-<hex>`, following the referrers tag schema.",
+
+		ReadContext: dataSourceOrasReferrersRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the remote artifact, including any tags or SHA256 repo digests.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"artifact_type": {
+				Description: "Only list the referrers with this artifact type. The filter is sent to the registry, which applies it server-side when supported. " +
+					"By default all referrers are listed.",
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"signer_identity": {
+				Description: "Only list the Notary and Cosign signatures whose certificate was issued to this identity, Fulcio style. " +
+					"Signatures without a certificate are excluded. The signatures themselves are not cryptographically verified.",
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subject": {
+							Description: "The expected email address or URI in the certificate, e.g. `https://github.com/org/repo/.github/workflows/release.yml@refs/heads/main`.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"issuer": {
+							Description: "The expected OIDC issuer recorded in the certificate, e.g. `https://token.actions.githubusercontent.com`. By default any issuer is accepted.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"aggregate_annotations": {
+				Description: "Set `annotations_by_digest` with the annotations of all referrers.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"annotations_by_digest": {
+				Description: "The annotations of the listed referrers keyed by their digest, each encoded as a JSON object to be decoded with `jsondecode`. " +
+					"Only set when `aggregate_annotations` is enabled.",
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"referrers": {
+				Description: "The referrers of the artifact.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"digest": {
+							Description: "The digest of the referrer manifest.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"media_type": {
+							Description: "The media type of the referrer manifest.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"artifact_type": {
+							Description: "The artifact type of the referrer.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"size": {
+							Description: "The size in bytes of the referrer manifest.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"annotations": {
+							Description: "The annotations of the referrer.",
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOrasReferrersRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	artifactType := d.Get("artifact_type").(string)
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	subject, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	var descs []ocispec.Descriptor
+	err = repo.Referrers(ctx, subject, artifactType, func(page []ocispec.Descriptor) error {
+		for _, referrer := range page {
+			// registries not supporting the filter return all referrers
+			if artifactType != "" && referrer.ArtifactType != artifactType {
+				continue
+			}
+			descs = append(descs, referrer)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var identity *signerIdentity
+	if v, ok := d.GetOk("signer_identity"); ok {
+		m := v.([]any)[0].(map[string]any)
+		identity = &signerIdentity{subject: m["subject"].(string), issuer: m["issuer"].(string)}
+	}
+
+	referrers := []any{}
+	for _, referrer := range descs {
+		if identity != nil {
+			ok, err := signedBy(ctx, repo, referrer, *identity)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		referrers = append(referrers, map[string]any{
+			"digest":        referrer.Digest.String(),
+			"media_type":    referrer.MediaType,
+			"artifact_type": referrer.ArtifactType,
+			"size":          referrer.Size,
+			"annotations":   referrer.Annotations,
+		})
+	}
+
+	_ = d.Set("referrers", referrers)
+
+	if d.Get("aggregate_annotations").(bool) {
+		annotations := make(map[string]any, len(referrers))
+		for _, referrer := range referrers {
+			m := referrer.(map[string]any)
+			a, _ := m["annotations"].(map[string]string)
+			if a == nil {
+				a = map[string]string{}
+			}
+			data, err := json.Marshal(a)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			annotations[m["digest"].(string)] = string(data)
+		}
+		_ = d.Set("annotations_by_digest", annotations)
+	}
+
+	d.SetId(subject.Digest.String())
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasReferrersRead(t *testing.T) {
+	subject := digest.FromString("subject")
+	sbom := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		Digest:       digest.FromString("sbom"),
+		Size:         10,
+		ArtifactType: "application/spdx+json",
+		Annotations:  map[string]string{"key": "value"},
+	}
+	signature := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		Digest:       digest.FromString("signature"),
+		Size:         20,
+		ArtifactType: "application/vnd.cncf.notary.signature",
+	}
+	index, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{sbom, signature},
+	})
+	tagSchema := "/v2/app/manifests/" + strings.Replace(subject.String(), ":", "-", 1)
+
+	for _, native := range []bool{true, false} {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/v2/app/manifests/v1":
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+				w.Header().Set("Docker-Content-Digest", subject.String())
+				w.Header().Set("Content-Length", "100")
+			case native && r.URL.Path == "/v2/app/referrers/"+subject.String():
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+				_, _ = w.Write(index)
+			case !native && r.URL.Path == tagSchema:
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+				_, _ = w.Write(index)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			t.Fatal("url.Parse() error =", err)
+		}
+		c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+		d := schema.TestResourceDataRaw(t, dataSourceOrasReferrers().Schema, map[string]any{
+			"reference":     u.Host + "/app:v1",
+			"artifact_type": sbom.ArtifactType,
+		})
+		if diags := dataSourceOrasReferrersRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("dataSourceOrasReferrersRead(native=%v) = %v", native, diags)
+		}
+		srv.Close()
+
+		referrers := d.Get("referrers").([]any)
+		if len(referrers) != 1 {
+			t.Fatalf("referrers(native=%v) = %v, want the SBOM only", native, referrers)
+		}
+		got := referrers[0].(map[string]any)
+		if got["digest"] != sbom.Digest.String() || got["artifact_type"] != sbom.ArtifactType || got["annotations"].(map[string]any)["key"] != "value" {
+			t.Errorf("referrers(native=%v) = %v, want %v", native, got, sbom)
+		}
+	}
+}
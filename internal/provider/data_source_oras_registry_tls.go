@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceOrasRegistryTLS() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the TLS certificate presented by a registry, e.g. to monitor it for changes or upcoming expiry. " +
+			"The connection uses the TLS settings of the provider, so the certificate is verified unless `insecure_skip_verify` is set for the registry.",
+
+		ReadContext: dataSourceOrasRegistryTLSRead,
+
+		Schema: map[string]*schema.Schema{
+			"registry": {
+				Description: "The host of the registry, e.g. `registry.example.com`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"subject": {
+				Description: "The subject of the certificate, e.g. `CN=registry.example.com`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"issuer": {
+				Description: "The issuer of the certificate.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"not_before": {
+				Description: "The start of the validity of the certificate, in RFC 3339 format.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"not_after": {
+				Description: "The expiry of the certificate, in RFC 3339 format.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"dns_names": {
+				Description: "The DNS names the certificate is valid for.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"sha256_fingerprint": {
+				Description: "The SHA-256 fingerprint of the certificate, hex encoded.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"tls_version": {
+				Description: "The TLS version of the connection, e.g. `TLS 1.3`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasRegistryTLSRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	host := d.Get("registry").(string)
+	reg, err := opts.NewRegistry(host)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if reg.PlainHTTP {
+		return diag.Errorf("registry %s is accessed over plain HTTP, it has no TLS certificate", host)
+	}
+
+	state, err := registryTLSState(ctx, opts.client.Client, reg.Reference.Host())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(state.PeerCertificates) == 0 {
+		return diag.Errorf("registry %s presented no certificate", host)
+	}
+
+	cert := state.PeerCertificates[0]
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	_ = d.Set("subject", cert.Subject.String())
+	_ = d.Set("issuer", cert.Issuer.String())
+	_ = d.Set("not_before", cert.NotBefore.UTC().Format(time.RFC3339))
+	_ = d.Set("not_after", cert.NotAfter.UTC().Format(time.RFC3339))
+	_ = d.Set("dns_names", cert.DNSNames)
+	_ = d.Set("sha256_fingerprint", fmt.Sprintf("%x", fingerprint))
+	_ = d.Set("tls_version", tls.VersionName(state.Version))
+
+	d.SetId(fmt.Sprintf("%x", fingerprint))
+
+	return nil
+}
+
+// registryTLSState returns the state of the TLS connection to the registry
+// host, observed on a request to its API base endpoint. The response status
+// doesn't matter, the base endpoint typically requires authentication.
+func registryTLSState(ctx context.Context, client *http.Client, host string) (*tls.ConnectionState, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+host+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.TLS == nil {
+		return nil, fmt.Errorf("connection to %s did not use TLS", host)
+	}
+	return resp.TLS, nil
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestDataSourceOrasRegistryTLSRead(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+	d := schema.TestResourceDataRaw(t, dataSourceOrasRegistryTLS().Schema, map[string]any{"registry": host})
+	if diags := dataSourceOrasRegistryTLSRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("dataSourceOrasRegistryTLSRead() = %v", diags)
+	}
+
+	cert := srv.Certificate()
+	if got, want := d.Get("sha256_fingerprint"), fmt.Sprintf("%x", sha256.Sum256(cert.Raw)); got != want {
+		t.Errorf("sha256_fingerprint = %s, want %s", got, want)
+	}
+	if got, want := d.Get("issuer"), cert.Issuer.String(); got != want {
+		t.Errorf("issuer = %s, want %s", got, want)
+	}
+	if d.Get("not_after") == "" || d.Get("tls_version") == "" {
+		t.Errorf("not_after = %q, tls_version = %q, want both set", d.Get("not_after"), d.Get("tls_version"))
+	}
+
+	c.plainHTTPHosts = map[string]bool{host: true}
+	if diags := dataSourceOrasRegistryTLSRead(context.Background(), d, c); !diags.HasError() {
+		t.Error("expected error for a plain HTTP registry")
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// sbomFormats maps the supported SBOM media types to the value of the format
+// attribute.
+var sbomFormats = map[string]string{
+	mediaTypeCycloneDXJSON: "cyclonedx-json",
+	mediaTypeCycloneDXXML:  "cyclonedx-xml",
+}
+
+func dataSourceOrasSBOM() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the components of the CycloneDX SBOM of an artifact, either attached to the artifact as referrer, or stored as artifact itself.",
+
+		ReadContext: dataSourceOrasSBOMRead,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the artifact the SBOM is attached to. When multiple CycloneDX SBOMs are attached, " +
+					"the most recently created one is read, preferring the JSON format.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"reference", "sbom_reference"},
+			},
+			"sbom_reference": {
+				Description: "The reference of the SBOM artifact itself.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"format": {
+				Description: "The format of the SBOM, `cyclonedx-json` or `cyclonedx-xml`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"components": {
+				Description: "The components of the SBOM, including the nested components.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Resource{Schema: sbomComponentSchema()},
+			},
+		},
+	}
+}
+
+func dataSourceOrasSBOMRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("sbom_reference").(string)
+	if reference == "" {
+		reference = d.Get("reference").(string)
+	}
+
+	repo, err := opts.newReadRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	if _, ok := d.GetOk("reference"); ok {
+		subject := desc
+		found := false
+		for _, artifactType := range []string{mediaTypeCycloneDXJSON, mediaTypeCycloneDXXML} {
+			desc, err = findReferrer(ctx, repo, subject, artifactType)
+			if err == nil {
+				found = true
+				break
+			}
+			if !errors.Is(err, errdef.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+		}
+		if !found {
+			return diag.Errorf("no CycloneDX SBOM is attached to %s", reference)
+		}
+	}
+
+	blobs, documents, err := fetchSBOMDocuments(ctx, repo, desc)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		blob     ocispec.Descriptor
+		document []byte
+	)
+	for i := range blobs {
+		if _, ok := sbomFormats[blobs[i].MediaType]; ok {
+			blob, document = blobs[i], documents[i]
+			break
+		}
+	}
+	if document == nil {
+		var mediaTypes []string
+		for _, b := range blobs {
+			mediaTypes = append(mediaTypes, b.MediaType)
+		}
+		return diag.Errorf("%s contains no CycloneDX SBOM, found unsupported formats %v", desc.Digest, mediaTypes)
+	}
+
+	sbom, err := parseSBOM(blob.MediaType, document)
+	if err != nil {
+		return diag.Errorf("SBOM %s: %s", desc.Digest, err)
+	}
+
+	var components []any
+	for _, c := range sbom {
+		components = append(components, c.toMap())
+	}
+
+	_ = d.Set("format", sbomFormats[blob.MediaType])
+	_ = d.Set("components", components)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
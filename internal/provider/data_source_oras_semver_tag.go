@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+func dataSourceOrasSemverTag() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resolves the highest semantic version tag of a repository satisfying a version constraint, e.g. the latest `1.x` release.",
+
+		ReadContext: dataSourceOrasSemverTagRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Description: "The repository of the artifact, without any tag or digest, e.g. `ghcr.io/org/app`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"constraint": {
+				Description:  "The version constraint the tag must satisfy, e.g. `~> 1.2` or `>= 1.0, < 2.0`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateSemverConstraint,
+			},
+			"include_prerelease": {
+				Description: "Also consider pre-release versions, e.g. `1.3.0-rc.1`. Pre-releases are matched against the constraint on their `major.minor.patch` version.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"tag": {
+				Description: "The highest tag satisfying the constraint.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"digest": {
+				Description: "The digest the tag resolved to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceOrasSemverTagRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repository := d.Get("repository").(string)
+	constraint, err := semver.NewConstraint(d.Get("constraint").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	includePrerelease := d.Get("include_prerelease").(bool)
+
+	repo, err := opts.newReadRepository(repository)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tags, err := listTags(ctx, repo, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		bestTag     string
+		bestVersion *semver.Version
+	)
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		core := version
+		if version.Prerelease() != "" {
+			if !includePrerelease {
+				continue
+			}
+			stripped, _ := version.SetPrerelease("")
+			core = &stripped
+		}
+		if !constraint.Check(core) {
+			continue
+		}
+		if bestVersion == nil || version.GreaterThan(bestVersion) {
+			bestTag, bestVersion = tag, version
+		}
+	}
+
+	if bestVersion == nil {
+		return diag.Errorf("no tag of %s satisfies the constraint '%s'", repository, constraint)
+	}
+
+	desc, err := repo.Resolve(ctx, bestTag)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("tag", bestTag)
+	_ = d.Set("digest", desc.Digest.String())
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
+
+// listTags returns all tags of repo, following the pagination of the
+// registry, starting after the tag last when set.
+func listTags(ctx context.Context, repo *remote.Repository, last string) ([]string, error) {
+	tags := []string{}
+	err := repo.Tags(ctx, last, func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	})
+	return tags, err
+}
+
+func validateSemverConstraint(v any, k string) (warnings []string, errs []error) {
+	if _, err := semver.NewConstraint(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid version constraint: %v", k, err))
+	}
+	return
+}
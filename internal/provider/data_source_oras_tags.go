@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceOrasTags() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the tags of a repository, following the pagination of the registry.",
+
+		ReadContext: dataSourceOrasTagsRead,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Description: "The repository, without any tag or digest, e.g. `ghcr.io/org/app`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"last": {
+				Description: "Only list the tags after this tag, in the order of the registry, typically lexical.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"regex": {
+				Description:  "Only list the tags matching this regular expression, e.g. `^v1\\.`. The tags are filtered by the provider, not by the registry.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"tags": {
+				Description: "The tags of the repository, in the order returned by the registry.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceOrasTagsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repository := d.Get("repository").(string)
+	repo, err := opts.newReadRepository(repository)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tags, err := listTags(ctx, repo, d.Get("last").(string))
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	if v, ok := d.GetOk("regex"); ok {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		tags = filterTags(tags, re)
+	}
+
+	_ = d.Set("tags", tags)
+
+	d.SetId(repository)
+
+	return nil
+}
+
+func filterTags(tags []string, re *regexp.Regexp) []string {
+	filtered := []string{}
+	for _, tag := range tags {
+		if re.MatchString(tag) {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestListTags(t *testing.T) {
+	pages := map[string][]string{
+		"":       {"v1.0.0", "v1.1.0"},
+		"v1.1.0": {"v2.0.0", "latest"},
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/app/tags/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		last := r.URL.Query().Get("last")
+		tags := pages[last]
+		if last == "" {
+			w.Header().Set("Link", `</v2/app/tags/list?last=v1.1.0>; rel="next"`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "app", "tags": tags})
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+	repo, err := c.NewRepository(u.Host + "/app")
+	if err != nil {
+		t.Fatal("NewRepository() error =", err)
+	}
+
+	tags, err := listTags(context.Background(), repo, "")
+	if err != nil {
+		t.Fatal("listTags() error =", err)
+	}
+	if want := []string{"v1.0.0", "v1.1.0", "v2.0.0", "latest"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("listTags() = %v, want %v", tags, want)
+	}
+
+	tags, err = listTags(context.Background(), repo, "v1.1.0")
+	if err != nil {
+		t.Fatal("listTags() error =", err)
+	}
+	if want := []string{"v2.0.0", "latest"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("listTags(last) = %v, want %v", tags, want)
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.1.0", "v2.0.0", "latest"}
+	if got, want := filterTags(tags, regexp.MustCompile(`^v1\.`)), []string{"v1.0.0", "v1.1.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterTags() = %v, want %v", got, want)
+	}
+	if got := filterTags(tags, regexp.MustCompile(`^v3`)); got == nil || len(got) != 0 {
+		t.Errorf("filterTags() = %#v, want empty list", got)
+	}
+}
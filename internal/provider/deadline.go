@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withDeadline wraps the functions of r so the context they receive, which is
+// passed down to every registry call, expires at the deadline of the provider,
+// and after the timeout of the provider for the single operation.
+func withDeadline(r *schema.Resource) {
+	wrap := func(fn func(context.Context, *schema.ResourceData, any) diag.Diagnostics) func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			c, ok := meta.(*clients)
+			if !ok {
+				return fn(ctx, d, meta)
+			}
+			if !c.deadline.IsZero() {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, c.deadline)
+				defer cancel()
+			}
+			if c.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+
+			diags := fn(ctx, d, meta)
+			if diags.HasError() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				diags = append(diags, timeoutDiagnostic(r, d, c))
+			}
+			return diags
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+}
+
+// timeoutDiagnostic explains that the operation failed as it did not complete
+// in time, naming the reference it operated on.
+func timeoutDiagnostic(r *schema.Resource, d *schema.ResourceData, c *clients) diag.Diagnostic {
+	target := "the registry"
+	for _, key := range []string{"reference", "name", "source_reference", "repository"} {
+		if s, ok := r.Schema[key]; ok && s.Type == schema.TypeString {
+			if v := d.Get(key).(string); v != "" {
+				target = v
+				break
+			}
+		}
+	}
+
+	var detail string
+	switch {
+	case !c.deadline.IsZero() && !time.Now().Before(c.deadline):
+		detail = fmt.Sprintf("The provider deadline of %s was reached.", c.deadline.Format(time.RFC3339))
+	case c.timeout > 0:
+		detail = fmt.Sprintf("The operation did not complete within the provider timeout of %s.", c.timeout)
+	default:
+		detail = "The operation did not complete in time."
+	}
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Timed out operating on %s", target),
+		Detail:   detail,
+	}
+}
+
+func validateDuration(v any, k string) (warnings []string, errs []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid duration, e.g. `10m`: %v", k, err))
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestWithDeadline(t *testing.T) {
+	cancelled := make(chan struct{}, 1)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled <- struct{}{}
+		case <-time.After(30 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+
+	r := New("test")().DataSourcesMap["oras_digests"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"references": []any{u.Host + "/app:latest"},
+	})
+	meta := &clients{
+		client:   &auth.Client{Client: srv.Client()},
+		deadline: time.Now().Add(100 * time.Millisecond),
+	}
+
+	start := time.Now()
+	diags := r.ReadContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatal("ReadContext() succeeded, want deadline exceeded")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("ReadContext() took %s, want it to stop at the deadline", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(10 * time.Second):
+		t.Error("registry request was not cancelled at the deadline")
+	}
+}
+
+func TestWithDeadline_timeout(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(30 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+
+	r := New("test")().DataSourcesMap["oras_digest"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"reference": u.Host + "/app:latest",
+	})
+	meta := &clients{
+		client:  &auth.Client{Client: srv.Client()},
+		timeout: 100 * time.Millisecond,
+	}
+
+	diags := r.ReadContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatal("ReadContext() succeeded, want timeout")
+	}
+	last := diags[len(diags)-1]
+	if want := "Timed out operating on " + u.Host + "/app:latest"; last.Summary != want {
+		t.Errorf("ReadContext() summary = %q, want %q", last.Summary, want)
+	}
+}
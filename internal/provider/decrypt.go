@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	decryptTypeAge = "age"
+	decryptTypeGPG = "gpg"
+)
+
+// decryptSchema returns the schema of the decrypt block. The key itself is
+// never part of the configuration, only where to read it from, so it doesn't
+// end up in the state.
+func decryptSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Decrypt the file content after pulling it, for artifacts distributing encrypted configuration.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Description:  "The encryption format of the file, either `age` or `gpg` (symmetric, passphrase based).",
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{decryptTypeAge, decryptTypeGPG}, false),
+				},
+				"key_file": {
+					Description:  "Path to a file containing the key: one or more age identities, or the GPG passphrase.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"decrypt.0.key_file", "decrypt.0.key_env"},
+				},
+				"key_env": {
+					Description:  "Name of the environment variable containing the key: one or more age identities, or the GPG passphrase.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"decrypt.0.key_file", "decrypt.0.key_env"},
+				},
+			},
+		},
+	}
+}
+
+// decrypter decrypts the content of pulled files.
+type decrypter struct {
+	typ string
+	key string
+}
+
+// expandDecrypter returns the decrypter configured by the decrypt block, or
+// nil when it is not set.
+func expandDecrypter(v []any) (*decrypter, error) {
+	if len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	m := v[0].(map[string]any)
+
+	keyFile := m["key_file"].(string)
+	keyEnv := m["key_env"].(string)
+
+	// exactly one of them is set, as enforced by the schema
+	var key string
+	if keyFile != "" {
+		path, err := homedir.Expand(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read decryption key: %w", err)
+		}
+		key = string(data)
+	} else {
+		var ok bool
+		if key, ok = os.LookupEnv(keyEnv); !ok {
+			return nil, fmt.Errorf("environment variable %s holding the decryption key is not set", keyEnv)
+		}
+	}
+
+	return &decrypter{typ: m["type"].(string), key: key}, nil
+}
+
+func (d *decrypter) decrypt(name string, data []byte) ([]byte, error) {
+	var (
+		plain []byte
+		err   error
+	)
+	switch d.typ {
+	case decryptTypeAge:
+		plain, err = decryptAge(d.key, data)
+	case decryptTypeGPG:
+		plain, err = decryptGPG(strings.TrimRight(d.key, "\r\n"), data)
+	default:
+		err = fmt.Errorf("unsupported encryption type '%s'", d.typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", name, err)
+	}
+	return plain, nil
+}
+
+func decryptAge(key string, data []byte) ([]byte, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid age identity: %w", err)
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(data), identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func decryptGPG(passphrase string, data []byte) ([]byte, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if block, err := armor.Decode(bytes.NewReader(data)); err == nil {
+		r = block.Body
+	}
+
+	prompted := false
+	md, err := openpgp.ReadMessage(r, nil, func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if !symmetric || prompted {
+			// the passphrase was rejected, fail instead of being asked again
+			return nil, errors.New("invalid passphrase")
+		}
+		prompted = true
+		return []byte(passphrase), nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// reading the body to the end also verifies the integrity of the message
+	return io.ReadAll(md.UnverifiedBody)
+}
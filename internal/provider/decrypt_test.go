@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestDecrypter(t *testing.T) {
+	plain := []byte("secret: value")
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal("age.GenerateX25519Identity() error =", err)
+	}
+	var ageData bytes.Buffer
+	w, err := age.Encrypt(&ageData, identity.Recipient())
+	if err != nil {
+		t.Fatal("age.Encrypt() error =", err)
+	}
+	write(t, w, plain)
+
+	var gpgData bytes.Buffer
+	w, err = openpgp.SymmetricallyEncrypt(&gpgData, []byte("passphrase"), nil, nil)
+	if err != nil {
+		t.Fatal("openpgp.SymmetricallyEncrypt() error =", err)
+	}
+	write(t, w, plain)
+
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal("age.GenerateX25519Identity() error =", err)
+	}
+
+	tests := []struct {
+		name    string
+		d       decrypter
+		data    []byte
+		wantErr bool
+	}{
+		{"age", decrypter{typ: decryptTypeAge, key: identity.String() + "\n"}, ageData.Bytes(), false},
+		{"age wrong identity", decrypter{typ: decryptTypeAge, key: other.String()}, ageData.Bytes(), true},
+		{"gpg", decrypter{typ: decryptTypeGPG, key: "passphrase\n"}, gpgData.Bytes(), false},
+		{"gpg wrong passphrase", decrypter{typ: decryptTypeGPG, key: "wrong"}, gpgData.Bytes(), true},
+		{"not encrypted", decrypter{typ: decryptTypeGPG, key: "passphrase"}, plain, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d.decrypt("config.yaml", tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plain) {
+				t.Errorf("decrypt() = %q, want %q", got, plain)
+			}
+		})
+	}
+}
+
+func TestExpandDecrypter_keyFileHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	homedir.DisableCache = true
+	t.Cleanup(func() { homedir.DisableCache = false })
+
+	if err := os.WriteFile(filepath.Join(home, "key.txt"), []byte("passphrase\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := expandDecrypter([]any{map[string]any{"type": decryptTypeGPG, "key_file": "~/key.txt", "key_env": ""}})
+	if err != nil {
+		t.Fatal("expandDecrypter() error =", err)
+	}
+	if d.key != "passphrase\n" {
+		t.Errorf("key = %q, want the content of ~/key.txt", d.key)
+	}
+}
+
+func write(t *testing.T, w io.WriteCloser, data []byte) {
+	t.Helper()
+	if _, err := w.Write(data); err != nil {
+		t.Fatal("Write() error =", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Close() error =", err)
+	}
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// ecrHostnamePattern matches the hostname of a private ECR registry, e.g.
+// 123456789012.dkr.ecr.eu-west-1.amazonaws.com.
+var ecrHostnamePattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ecrAPI is the part of the ECR client used to obtain authorization tokens.
+type ecrAPI interface {
+	GetAuthorizationToken(ctx context.Context, params *ecr.GetAuthorizationTokenInput, optFns ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error)
+}
+
+// ecrCredential obtains credentials for an ECR registry with the AWS SDK,
+// requesting a new authorization token when the previous one expires.
+type ecrCredential struct {
+	registryID string
+	client     ecrAPI
+
+	mu        sync.Mutex
+	cached    auth.Credential
+	expiresAt time.Time
+}
+
+func expandECRCredential(hostname string, m map[string]any) (*ecrCredential, error) {
+	match := ecrHostnamePattern.FindStringSubmatch(hostname)
+	if match == nil {
+		return nil, fmt.Errorf("'%s' is not the address of an ECR registry", hostname)
+	}
+
+	region := m["region"].(string)
+	if region == "" {
+		region = match[2]
+	}
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if profile := m["profile"].(string); profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	// only reads the environment and the shared configuration, the AWS
+	// credentials themselves are retrieved when a token is requested
+	cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return &ecrCredential{registryID: match[1], client: ecr.NewFromConfig(cfg)}, nil
+}
+
+// credential returns the authorization token of the registry, requesting a
+// new token when the previous one expires within five minutes.
+func (e *ecrCredential) credential(ctx context.Context) (auth.Credential, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cached == auth.EmptyCredential || time.Now().Add(5*time.Minute).After(e.expiresAt) {
+		cred, expiresAt, err := e.requestToken(ctx)
+		if err != nil {
+			return auth.EmptyCredential, fmt.Errorf("failed to get ECR authorization token: %w", err)
+		}
+		e.cached, e.expiresAt = cred, expiresAt
+	}
+	return e.cached, nil
+}
+
+func (e *ecrCredential) requestToken(ctx context.Context) (auth.Credential, time.Time, error) {
+	out, err := e.client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{
+		RegistryIds: []string{e.registryID},
+	})
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+	if len(out.AuthorizationData) == 0 {
+		return auth.EmptyCredential, time.Time{}, errors.New("response has no authorization data")
+	}
+	data := out.AuthorizationData[0]
+
+	token, err := base64.StdEncoding.DecodeString(aws.ToString(data.AuthorizationToken))
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("invalid authorization token: %w", err)
+	}
+	username, password, ok := strings.Cut(string(token), ":")
+	if !ok {
+		return auth.EmptyCredential, time.Time{}, errors.New("invalid authorization token: missing username")
+	}
+	return auth.Credential{Username: username, Password: password}, aws.ToTime(data.ExpiresAt), nil
+}
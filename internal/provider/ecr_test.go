@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+type fakeECR struct {
+	requests  int
+	expiresIn time.Duration
+}
+
+func (f *fakeECR) GetAuthorizationToken(_ context.Context, params *ecr.GetAuthorizationTokenInput, _ ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error) {
+	f.requests++
+	token := base64.StdEncoding.EncodeToString([]byte("AWS:token-for-" + params.RegistryIds[0]))
+	return &ecr.GetAuthorizationTokenOutput{
+		AuthorizationData: []types.AuthorizationData{{
+			AuthorizationToken: aws.String(token),
+			ExpiresAt:          aws.Time(time.Now().Add(f.expiresIn)),
+		}},
+	}, nil
+}
+
+func TestECRCredential(t *testing.T) {
+	for _, tt := range []struct {
+		expiresIn time.Duration
+		requests  int
+	}{
+		{expiresIn: 12 * time.Hour, requests: 1},
+		{expiresIn: time.Minute, requests: 2},
+	} {
+		client := &fakeECR{expiresIn: tt.expiresIn}
+		e := &ecrCredential{registryID: "123456789012", client: client}
+
+		for i := 0; i < 2; i++ {
+			got, err := e.credential(context.Background())
+			if err != nil {
+				t.Fatal("credential() error =", err)
+			}
+			if want := (auth.Credential{Username: "AWS", Password: "token-for-123456789012"}); got != want {
+				t.Errorf("credential() = %v, want %v", got, want)
+			}
+		}
+		if client.requests != tt.requests {
+			t.Errorf("token expiring in %s requested %d times, want %d", tt.expiresIn, client.requests, tt.requests)
+		}
+	}
+}
+
+func TestExpandECRCredential(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+
+	e, err := expandECRCredential("123456789012.dkr.ecr.eu-west-1.amazonaws.com", map[string]any{"region": "", "profile": ""})
+	if err != nil {
+		t.Fatal("expandECRCredential() error =", err)
+	}
+	if e.registryID != "123456789012" {
+		t.Errorf("registryID = %s, want 123456789012", e.registryID)
+	}
+
+	if _, err := expandECRCredential("ghcr.io", map[string]any{"region": "", "profile": ""}); err == nil {
+		t.Error("expandECRCredential() error = nil, want error for a registry outside ECR")
+	}
+}
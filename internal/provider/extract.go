@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// extractLayers unpacks the gzip compressed tar layers of a manifest pulled
+// into dir. The file store only unpacks the directories pushed by oras, which
+// carry a title, and keeps the untitled image layers in memory instead.
+func extractLayers(ctx context.Context, fetcher content.Fetcher, dir string, layers []ocispec.Descriptor) error {
+	for _, layer := range layers {
+		if layer.MediaType != ocispec.MediaTypeImageLayerGzip || layer.Annotations[ocispec.AnnotationTitle] != "" {
+			continue
+		}
+
+		rc, err := fetcher.Fetch(ctx, layer)
+		if err != nil {
+			return err
+		}
+		err = extractTarGzip(dir, content.NewVerifyReader(rc, layer))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to extract layer %s: %w", layer.Digest, err)
+		}
+	}
+	return nil
+}
+
+// Whiteout files of image layers, marking the paths removed from the layers
+// below.
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// extractTarGzip extracts the gzip compressed tar archive read from r into
+// dir, applying the whiteouts of image layers to the content of the layers
+// extracted before. Entries pointing outside of dir are rejected, and no
+// entry is ever written through a symlink.
+func extractTarGzip(dir string, r io.Reader) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	// the paths extracted from this layer and their parents, which are kept
+	// by opaque whiteouts
+	written := make(map[string]bool)
+	markWritten := func(target string) {
+		for p := target; p != dir && !written[p]; p = filepath.Dir(p) {
+			written[p] = true
+		}
+	}
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := securePath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		if target == dir {
+			continue
+		}
+		if err := checkNoSymlinks(dir, filepath.Dir(target)); err != nil {
+			return err
+		}
+
+		if base := filepath.Base(target); strings.HasPrefix(base, whiteoutPrefix) {
+			if base == whiteoutOpaque {
+				err = removeOpaque(filepath.Dir(target), written)
+			} else {
+				err = os.RemoveAll(filepath.Join(filepath.Dir(target), strings.TrimPrefix(base, whiteoutPrefix)))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to apply whiteout %s: %w", header.Name, err)
+			}
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := removeSymlink(target); err != nil {
+				return err
+			}
+			if err := writeTarFile(target, header, tr); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			linkname, err := secureLinkname(dir, target, header.Linkname)
+			if err != nil {
+				return fmt.Errorf("symlink %s: %w", header.Name, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := removeNonDir(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := securePath(dir, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := removeNonDir(target); err != nil {
+				return err
+			}
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		markWritten(target)
+	}
+}
+
+func writeTarFile(target string, header *tar.Header, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	fp, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fp, r); err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
+
+// securePath returns the path of name inside dir, failing when name points
+// outside of dir.
+func securePath(dir, name string) (string, error) {
+	target := filepath.Join(dir, name)
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s points outside of the extraction directory", name)
+	}
+	return target, nil
+}
+
+// checkNoSymlinks fails when one of the existing components of path below dir
+// is a symlink, which a later entry of an archive could use to write outside
+// of dir.
+func checkNoSymlinks(dir, path string) error {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." {
+		return err
+	}
+	current := dir
+	for _, component := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, component)
+		fi, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("path %s traverses the symlink %s", path, current)
+		}
+	}
+	return nil
+}
+
+// secureLinkname returns the target of the symlink at path, resolved against
+// dir when absolute. The target is rewritten relative to the directory of the
+// symlink and without `..` after its leading ones, so it can only traverse
+// the real parent directories of the symlink and always resolves inside dir,
+// even when other symlinks are part of the target.
+func secureLinkname(dir, path, linkname string) (string, error) {
+	var resolved string
+	if filepath.IsAbs(linkname) {
+		resolved = filepath.Join(dir, linkname)
+	} else {
+		resolved = filepath.Join(filepath.Dir(path), linkname)
+	}
+	rel, err := filepath.Rel(dir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("target %s points outside of the extraction directory", linkname)
+	}
+	return filepath.Rel(filepath.Dir(path), resolved)
+}
+
+// removeSymlink removes path when it is a symlink, so it is replaced rather
+// than written through.
+func removeSymlink(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		return os.Remove(path)
+	}
+	return nil
+}
+
+// removeNonDir removes path unless it is a directory, which a link would
+// replace.
+func removeNonDir(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("cannot replace directory %s by a link", path)
+	}
+	return os.Remove(path)
+}
+
+// removeOpaque applies an opaque whiteout to dir, removing all its content
+// except the paths written by the current layer.
+func removeOpaque(dir string, written map[string]bool) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if path == dir || written[path] {
+			return nil
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// tarGzip returns a gzip compressed tar archive of files, keyed by path.
+func tarGzip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, data := range files {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal("WriteHeader() error =", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal("Write() error =", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("tar.Writer.Close() error =", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal("gzip.Writer.Close() error =", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractLayers(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	imageFiles := map[string][]byte{
+		"etc/config.yaml": []byte("key: value\n"),
+		"bin/tool":        {0x00, 0x1f, 0x8b, 0xff, 0x7f},
+	}
+	dirFiles := map[string][]byte{
+		"docs/README.md": []byte("# docs\n"),
+	}
+
+	imageLayer := pushBlob(t, store, ocispec.MediaTypeImageLayerGzip, tarGzip(t, imageFiles))
+	dirLayer := pushBlob(t, store, ocispec.MediaTypeImageLayerGzip, tarGzip(t, dirFiles))
+	dirLayer.Annotations = map[string]string{
+		ocispec.AnnotationTitle: "docs",
+		file.AnnotationUnpack:   "true",
+	}
+	manifest := pushManifest(t, store, pushBlob(t, store, "application/vnd.test.config", []byte("{}")), imageLayer, dirLayer)
+	if err := store.Tag(ctx, manifest, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	dir := t.TempDir()
+	dst, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	if _, err := oras.Copy(ctx, store, "v1", dst, "v1", oras.DefaultCopyOptions); err != nil {
+		t.Fatal("oras.Copy() error =", err)
+	}
+	if err := extractLayers(ctx, dst, dir, []ocispec.Descriptor{imageLayer, dirLayer}); err != nil {
+		t.Fatal("extractLayers() error =", err)
+	}
+
+	for _, files := range []map[string][]byte{imageFiles, dirFiles} {
+		for name, want := range files {
+			got, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal("os.ReadFile() error =", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("content of %s = %v, want %v", name, got, want)
+			}
+		}
+	}
+}
+
+func TestExtractTarGzip_pathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	err := extractTarGzip(filepath.Join(dir, "out"), bytes.NewReader(tarGzip(t, map[string][]byte{
+		"../evil": []byte("evil"),
+	})))
+	if err == nil {
+		t.Error("extractTarGzip() error = nil, want error for a path outside of the directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil")); err == nil {
+		t.Error("extractTarGzip() wrote a file outside of the directory")
+	}
+}
+
+// tarEntry is an entry of an archive built by tarGzipEntries.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	data     string
+}
+
+// tarGzipEntries returns a gzip compressed tar archive of entries, in order.
+func tarGzipEntries(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.data)), Typeflag: e.typeflag, Linkname: e.linkname}
+		if e.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal("WriteHeader() error =", err)
+		}
+		if _, err := tw.Write([]byte(e.data)); err != nil {
+			t.Fatal("Write() error =", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("tar.Writer.Close() error =", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal("gzip.Writer.Close() error =", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTarGzip_symlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr bool
+	}{
+		{name: "relative target outside", entries: []tarEntry{
+			{name: "a", typeflag: tar.TypeSymlink, linkname: ".."},
+		}, wantErr: true},
+		{name: "write through symlink", entries: []tarEntry{
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "a/evil", typeflag: tar.TypeReg, data: "evil"},
+		}, wantErr: true},
+		{name: "link chain", entries: []tarEntry{
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "."},
+			{name: "b", typeflag: tar.TypeSymlink, linkname: "a/.."},
+			{name: "b/evil", typeflag: tar.TypeReg, data: "evil"},
+		}, wantErr: true},
+		{name: "replace symlink by file", entries: []tarEntry{
+			{name: "a", typeflag: tar.TypeSymlink, linkname: "/target"},
+			{name: "a", typeflag: tar.TypeReg, data: "file"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "out")
+			err := extractTarGzip(dir, bytes.NewReader(tarGzipEntries(t, tt.entries...)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractTarGzip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := os.Lstat(filepath.Join(root, "evil")); err == nil {
+				t.Error("extractTarGzip() wrote a file outside of the directory")
+			}
+			if _, err := os.Lstat(filepath.Join(root, "target")); err == nil {
+				t.Error("extractTarGzip() wrote through a symlink")
+			}
+		})
+	}
+}
+
+func TestExtractTarGzip_absoluteSymlink(t *testing.T) {
+	dir := t.TempDir()
+	err := extractTarGzip(dir, bytes.NewReader(tarGzipEntries(t,
+		tarEntry{name: "bin/busybox", typeflag: tar.TypeReg, data: "busybox"},
+		tarEntry{name: "usr/bin/sh", typeflag: tar.TypeSymlink, linkname: "/bin/busybox"},
+	)))
+	if err != nil {
+		t.Fatal("extractTarGzip() error =", err)
+	}
+	if got, err := os.Readlink(filepath.Join(dir, "usr", "bin", "sh")); err != nil || got != filepath.Join("..", "..", "bin", "busybox") {
+		t.Errorf("Readlink() = %s, %v, want a link relative to the directory", got, err)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "usr", "bin", "sh")); err != nil || string(got) != "busybox" {
+		t.Errorf("ReadFile() = %q, %v, want %q", got, err, "busybox")
+	}
+}
+
+func TestExtractTarGzip_whiteouts(t *testing.T) {
+	dir := t.TempDir()
+	lower := tarGzipEntries(t,
+		tarEntry{name: "etc/removed", typeflag: tar.TypeReg, data: "removed"},
+		tarEntry{name: "etc/kept", typeflag: tar.TypeReg, data: "kept"},
+		tarEntry{name: "opaque/old", typeflag: tar.TypeReg, data: "old"},
+		tarEntry{name: "opaque/sub/old", typeflag: tar.TypeReg, data: "old"},
+	)
+	upper := tarGzipEntries(t,
+		tarEntry{name: "etc/.wh.removed", typeflag: tar.TypeReg},
+		tarEntry{name: "opaque/new", typeflag: tar.TypeReg, data: "new"},
+		tarEntry{name: "opaque/.wh..wh..opq", typeflag: tar.TypeReg},
+	)
+	for _, layer := range [][]byte{lower, upper} {
+		if err := extractTarGzip(dir, bytes.NewReader(layer)); err != nil {
+			t.Fatal("extractTarGzip() error =", err)
+		}
+	}
+
+	for name, wantExists := range map[string]bool{
+		"etc/removed":         false,
+		"etc/.wh.removed":     false,
+		"etc/kept":            true,
+		"opaque/old":          false,
+		"opaque/sub":          false,
+		"opaque/new":          true,
+		"opaque/.wh..wh..opq": false,
+	} {
+		if _, err := os.Lstat(filepath.Join(dir, name)); (err == nil) != wantExists {
+			t.Errorf("%s exists = %v, want %v", name, err == nil, wantExists)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// flattenDir moves the files in the sub directories of dir into dir itself,
+// named by the last component of their path, and removes the sub directories.
+// Nothing is moved when two files end up with the same name.
+func flattenDir(dir string) error {
+	byName := make(map[string][]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		byName[path.Base(rel)] = append(byName[path.Base(rel)], rel)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var collisions []string
+	for _, paths := range byName {
+		if len(paths) > 1 {
+			sort.Strings(paths)
+			collisions = append(collisions, strings.Join(paths, ", "))
+		}
+	}
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return fmt.Errorf("can't flatten %s, files have the same name: %s", dir, strings.Join(collisions, "; "))
+	}
+
+	for name, paths := range byName {
+		if paths[0] == name {
+			continue
+		}
+		if err := os.Rename(filepath.Join(dir, filepath.FromSlash(paths[0])), filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		// only empty directories remain after moving the files
+		if entry.IsDir() {
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+}
+
+func TestFlattenDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"top.yaml":          "top",
+		"a/config.yaml":     "config",
+		"a/b/c/values.yaml": "values",
+	})
+
+	if err := flattenDir(dir); err != nil {
+		t.Fatal("flattenDir() error =", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if got, want := strings.Join(names, ","), "config.yaml,top.yaml,values.yaml"; got != want {
+		t.Errorf("flattened entries = %s, want %s", got, want)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "values.yaml")); string(data) != "values" {
+		t.Errorf("values.yaml = %q, want the content of a/b/c/values.yaml", data)
+	}
+}
+
+func TestFlattenDir_collision(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a/config.yaml": "a",
+		"b/config.yaml": "b",
+	})
+
+	err := flattenDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "a/config.yaml, b/config.yaml") {
+		t.Errorf("flattenDir() error = %v, want the colliding files", err)
+	}
+	// nothing was moved
+	if _, err := os.Stat(filepath.Join(dir, "a", "config.yaml")); err != nil {
+		t.Errorf("a/config.yaml was moved despite the collision: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// gcpUsername is the username accepted by Artifact Registry and Container
+// Registry together with an OAuth access token as password.
+const gcpUsername = "oauth2accesstoken"
+
+// gcpScope is the OAuth scope of the access tokens requested for the registry.
+const gcpScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// gcpCredential obtains credentials for Google Artifact Registry and Container
+// Registry from the application default credentials, like
+// docker-credential-gcr does.
+type gcpCredential struct {
+	tokens oauth2.TokenSource
+}
+
+func expandGCPCredential() (*gcpCredential, error) {
+	creds, err := google.FindDefaultCredentials(context.Background(), gcpScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Google application default credentials: %w", err)
+	}
+	return &gcpCredential{tokens: creds.TokenSource}, nil
+}
+
+// credential returns the access token as password, the token source takes
+// care of refreshing it when it expires.
+func (g *gcpCredential) credential(_ context.Context) (auth.Credential, error) {
+	token, err := g.tokens.Token()
+	if err != nil {
+		return auth.EmptyCredential, fmt.Errorf("failed to get Google access token: %w", err)
+	}
+	return auth.Credential{Username: gcpUsername, Password: token.AccessToken}, nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestGCPCredential(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "gcp-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer srv.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "pusher@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credsFile := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(credsFile, creds, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", credsFile)
+
+	g, err := expandGCPCredential()
+	if err != nil {
+		t.Fatal("expandGCPCredential() error =", err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err := g.credential(context.Background())
+		if err != nil {
+			t.Fatal("credential() error =", err)
+		}
+		if want := (auth.Credential{Username: gcpUsername, Password: "gcp-token"}); got != want {
+			t.Errorf("credential() = %v, want %v", got, want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("token requested %d times, want 1", requests)
+	}
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// Environment variables set by GitHub Actions in jobs with the id-token
+// permission.
+const (
+	envGitHubOIDCRequestURL   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	envGitHubOIDCRequestToken = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+)
+
+// githubOIDCCredential obtains credentials from the OIDC token of a GitHub
+// Actions job, requesting a new token when the previous one expires.
+type githubOIDCCredential struct {
+	requestURL   string
+	requestToken string
+	audience     string
+	username     string
+	client       *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func expandGitHubOIDCCredential(m map[string]any) (*githubOIDCCredential, error) {
+	requestURL, requestToken := os.Getenv(envGitHubOIDCRequestURL), os.Getenv(envGitHubOIDCRequestToken)
+	if requestURL == "" || requestToken == "" {
+		return nil, fmt.Errorf("github_oidc requires the %s and %s environment variables, "+
+			"only set in GitHub Actions jobs with the `id-token: write` permission", envGitHubOIDCRequestURL, envGitHubOIDCRequestToken)
+	}
+
+	username := m["username"].(string)
+	if username == "" {
+		username = os.Getenv("GITHUB_ACTOR")
+	}
+
+	return &githubOIDCCredential{
+		requestURL:   requestURL,
+		requestToken: requestToken,
+		audience:     m["audience"].(string),
+		username:     username,
+		client:       http.DefaultClient,
+	}, nil
+}
+
+// credential returns the OIDC token as password, requesting a new token when
+// the previous one expires within a minute.
+func (g *githubOIDCCredential) credential(ctx context.Context) (auth.Credential, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token == "" || time.Now().Add(time.Minute).After(g.expiresAt) {
+		token, err := g.requestIDToken(ctx)
+		if err != nil {
+			return auth.EmptyCredential, fmt.Errorf("failed to get GitHub Actions OIDC token: %w", err)
+		}
+		g.token, g.expiresAt = token, jwtExpiry(token)
+	}
+
+	return auth.Credential{Username: g.username, Password: g.token}, nil
+}
+
+func (g *githubOIDCCredential) requestIDToken(ctx context.Context) (string, error) {
+	u, err := url.Parse(g.requestURL)
+	if err != nil {
+		return "", err
+	}
+	if g.audience != "" {
+		q := u.Query()
+		q.Set("audience", g.audience)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var v struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return "", err
+	}
+	if v.Value == "" {
+		return "", errors.New("response has no token")
+	}
+	return v.Value, nil
+}
+
+// jwtExpiry returns the expiry of a JWT, without verifying it, or the zero
+// time when it has none.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGitHubOIDCCredential(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.Header.Get("Authorization"); got != "Bearer request-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("audience"); got != "ghcr.io" {
+			t.Errorf("audience = %q", got)
+		}
+		// the first token expires immediately, the second one in an hour
+		exp := time.Now().Add(time.Duration(requests-1) * time.Hour).Unix()
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp)))
+		fmt.Fprintf(w, `{"value": "header.%s.sig%d"}`, payload, requests)
+	}))
+	defer srv.Close()
+
+	t.Setenv(envGitHubOIDCRequestURL, srv.URL+"?api-version=2.0")
+	t.Setenv(envGitHubOIDCRequestToken, "request-token")
+	t.Setenv("GITHUB_ACTOR", "octocat")
+
+	g, err := expandGitHubOIDCCredential(map[string]any{"audience": "ghcr.io", "username": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		cred, err := g.credential(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cred.Username != "octocat" || cred.Password == "" {
+			t.Errorf("credential() = %v", cred)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("token requested %d times, want 2", requests)
+	}
+}
+
+func TestGitHubOIDCCredential_missingEnv(t *testing.T) {
+	t.Setenv(envGitHubOIDCRequestURL, "")
+	t.Setenv(envGitHubOIDCRequestToken, "")
+
+	if _, err := expandGitHubOIDCCredential(map[string]any{"audience": "", "username": ""}); err == nil {
+		t.Error("expected error without the GitHub Actions environment")
+	}
+}
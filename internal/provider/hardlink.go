@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fileKey identifies the content of a regular file.
+type fileKey struct {
+	size     int64
+	checksum [sha256.Size]byte
+}
+
+// hardlinkDuplicates replaces the files extracted from layers into root that
+// have identical content by hardlinks to a single copy, returning the number
+// of files replaced. Files are left as copies when the filesystem doesn't
+// support hardlinks.
+func hardlinkDuplicates(root string, layers []ocispec.Descriptor) (int, error) {
+	var paths []string
+	for _, layer := range layers {
+		title := layer.Annotations[ocispec.AnnotationTitle]
+		if title == "" {
+			continue
+		}
+		err := filepath.WalkDir(filepath.Join(root, title), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.Type().IsRegular() {
+				paths = append(paths, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	linked := 0
+	originals := make(map[fileKey]string)
+	for _, p := range paths {
+		key, err := checksumFile(p)
+		if err != nil {
+			return linked, err
+		}
+
+		original, ok := originals[key]
+		if !ok {
+			originals[key] = p
+			continue
+		}
+
+		ok, err = replaceWithHardlink(original, p)
+		if err != nil {
+			return linked, err
+		}
+		if ok {
+			linked++
+		}
+	}
+
+	return linked, nil
+}
+
+// replaceWithHardlink replaces p by a hardlink to original. It reports false
+// when the filesystem doesn't support the hardlink, leaving p untouched.
+func replaceWithHardlink(original, p string) (bool, error) {
+	originalInfo, err := os.Stat(original)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return false, err
+	}
+	if os.SameFile(originalInfo, info) || originalInfo.Mode() != info.Mode() {
+		// already linked, or the copies differ in permissions
+		return false, nil
+	}
+
+	// link next to p first, so p is replaced atomically
+	tmp := p + ".oras-link"
+	if err := os.Link(original, tmp); err != nil {
+		return false, nil
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		_ = os.Remove(tmp)
+		return false, err
+	}
+	return true, nil
+}
+
+func checksumFile(p string) (fileKey, error) {
+	fp, err := os.Open(p)
+	if err != nil {
+		return fileKey{}, err
+	}
+	defer fp.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, fp)
+	if err != nil {
+		return fileKey{}, err
+	}
+
+	key := fileKey{size: size}
+	copy(key.checksum[:], h.Sum(nil))
+	return key, nil
+}
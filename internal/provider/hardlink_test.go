@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestHardlinkDuplicates(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	duplicate := pushBlob(t, store, "application/vnd.test.file", []byte("duplicate"))
+	unique := pushBlob(t, store, "application/vnd.test.file", []byte("unique"))
+	layer := func(title string, desc ocispec.Descriptor) ocispec.Descriptor {
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: title}
+		return desc
+	}
+	layers := []ocispec.Descriptor{
+		layer("a.txt", duplicate),
+		layer("b.txt", duplicate),
+		layer("c.txt", unique),
+		layer("d.txt", duplicate),
+	}
+	manifest := pushManifest(t, store, pushBlob(t, store, "application/vnd.test.config", []byte("{}")), layers...)
+	if err := store.Tag(ctx, manifest, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	dir := t.TempDir()
+	dst, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	if _, err := oras.Copy(ctx, store, "v1", dst, "v1", oras.DefaultCopyOptions); err != nil {
+		t.Fatal("oras.Copy() error =", err)
+	}
+
+	got, err := hardlinkDuplicates(dir, layers)
+	if err != nil {
+		t.Fatal("hardlinkDuplicates() error =", err)
+	}
+	if got != 2 {
+		t.Errorf("hardlinkDuplicates() = %d, want 2", got)
+	}
+
+	stat := func(name string) os.FileInfo {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal("os.Stat() error =", err)
+		}
+		return info
+	}
+	if !os.SameFile(stat("a.txt"), stat("b.txt")) || !os.SameFile(stat("a.txt"), stat("d.txt")) {
+		t.Error("duplicate files are not hardlinked")
+	}
+	if os.SameFile(stat("a.txt"), stat("c.txt")) {
+		t.Error("unique file is hardlinked")
+	}
+
+	want := map[string]string{"a.txt": "duplicate", "b.txt": "duplicate", "c.txt": "unique", "d.txt": "duplicate"}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal("os.ReadFile() error =", err)
+		}
+		if string(data) != content {
+			t.Errorf("content of %s = %q, want %q", name, data, content)
+		}
+	}
+
+	// running it again is a no-op
+	if got, err := hardlinkDuplicates(dir, layers); err != nil || got != 0 {
+		t.Errorf("hardlinkDuplicates() = %d, %v, want 0, nil", got, err)
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// errKeychainNotFound is returned by keychainGet when the keychain has no
+// entry for the service and account.
+var errKeychainNotFound = errors.New("keychain entry not found")
+
+// keychainGet reads the secret stored in the OS keychain, it is a variable so
+// tests can replace it.
+var keychainGet = readKeychain
+
+// keychainCredential obtains the password of a registry from the keychain of
+// the operating system, i.e. the macOS Keychain or the Windows Credential
+// Manager.
+type keychainCredential struct {
+	service  string
+	username string
+}
+
+func expandKeychainCredential(hostname string, m map[string]any) (*keychainCredential, error) {
+	if !keychainSupported {
+		return nil, fmt.Errorf("keychain credentials are not supported on %s", runtime.GOOS)
+	}
+	service := m["service"].(string)
+	if service == "" {
+		service = hostname
+	}
+	return &keychainCredential{service: service, username: m["username"].(string)}, nil
+}
+
+// credential reads the keychain each time, so changes to the entry are picked
+// up without reconfiguring the provider.
+func (k *keychainCredential) credential(_ context.Context) (auth.Credential, error) {
+	secret, err := keychainGet(k.service, k.username)
+	if errors.Is(err, errKeychainNotFound) {
+		return auth.EmptyCredential, fmt.Errorf("no keychain entry for service '%s' and account '%s'", k.service, k.username)
+	}
+	if err != nil {
+		return auth.EmptyCredential, fmt.Errorf("failed to read keychain entry for service '%s': %w", k.service, err)
+	}
+	return auth.Credential{Username: k.username, Password: secret}, nil
+}
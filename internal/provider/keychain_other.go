@@ -0,0 +1,13 @@
+//go:build !darwin && !windows
+
+package provider
+
+import "errors"
+
+// keychainSupported is false as keychain credentials are only supported on
+// macOS and Windows.
+const keychainSupported = false
+
+func readKeychain(_, _ string) (string, error) {
+	return "", errors.New("keychain credentials are not supported on this platform")
+}
@@ -0,0 +1,19 @@
+//go:build darwin || windows
+
+package provider
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+const keychainSupported = true
+
+func readKeychain(service, username string) (string, error) {
+	secret, err := keyring.Get(service, username)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", errKeychainNotFound
+	}
+	return secret, err
+}
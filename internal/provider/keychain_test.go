@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestKeychainCredential(t *testing.T) {
+	defer func(get func(string, string) (string, error)) { keychainGet = get }(keychainGet)
+	keychainGet = func(service, username string) (string, error) {
+		if service == "registry.example.com" && username == "user" {
+			return "secret", nil
+		}
+		return "", errKeychainNotFound
+	}
+
+	k := &keychainCredential{service: "registry.example.com", username: "user"}
+	got, err := k.credential(context.Background())
+	if err != nil {
+		t.Fatal("credential() error =", err)
+	}
+	if want := (auth.Credential{Username: "user", Password: "secret"}); got != want {
+		t.Errorf("credential() = %v, want %v", got, want)
+	}
+
+	k = &keychainCredential{service: "registry.example.com", username: "other"}
+	if _, err := k.credential(context.Background()); err == nil || !strings.Contains(err.Error(), "no keychain entry") {
+		t.Errorf("credential() error = %v, want a missing entry error", err)
+	}
+}
+
+func TestExpandKeychainCredential(t *testing.T) {
+	k, err := expandKeychainCredential("registry.example.com", map[string]any{"service": "", "username": "user"})
+	if !keychainSupported {
+		if err == nil {
+			t.Error("expandKeychainCredential() error = nil, want unsupported platform error")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal("expandKeychainCredential() error =", err)
+	}
+	if k.service != "registry.example.com" {
+		t.Errorf("service = %s, want the hostname", k.service)
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// explainResolveError adds an actionable explanation to err, returned while
+// resolving a reference in repo, when the registry turns out to only
+// implement the legacy Docker Registry v1 API.
+func explainResolveError(ctx context.Context, repo *remote.Repository, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if isV1OnlyRegistry(ctx, repo) {
+		return fmt.Errorf("registry %s only implements the legacy Docker Registry v1 API, which is not supported; "+
+			"upgrade the registry to one implementing the OCI distribution (v2) API: %w", repo.Reference.Registry, err)
+	}
+	return err
+}
+
+// isV1OnlyRegistry reports whether the registry of repo lacks the v2 API
+// base endpoint but answers the v1 ping endpoint.
+func isV1OnlyRegistry(ctx context.Context, repo *remote.Repository) bool {
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	base := fmt.Sprintf("%s://%s", scheme, repo.Reference.Host())
+
+	status := func(path string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+		if err != nil {
+			return 0
+		}
+		resp, err := repo.Client.Do(req)
+		if err != nil {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// a v2 registry answers the base endpoint with 200, or 401 when
+	// authentication is required
+	if status("/v2/") != http.StatusNotFound {
+		return false
+	}
+	return status("/v1/_ping") == http.StatusOK
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestExplainResolveError(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes map[string]int
+		wantV1 bool
+	}{
+		{"v1 only", map[string]int{"/v1/_ping": http.StatusOK}, true},
+		{"v2", map[string]int{"/v2/": http.StatusOK}, false},
+		{"v2 with auth", map[string]int{"/v2/": http.StatusUnauthorized, "/v1/_ping": http.StatusOK}, false},
+		{"unknown", map[string]int{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if status, ok := tt.routes[r.URL.Path]; ok {
+					w.WriteHeader(status)
+					return
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("<html>not found</html>"))
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal("url.Parse() error =", err)
+			}
+
+			c := &clients{client: &auth.Client{Client: srv.Client()}}
+			_, err = c.resolve(context.Background(), u.Host+"/legacy/app:latest")
+			if err == nil {
+				t.Fatal("resolve() error = nil, want error")
+			}
+			if got := strings.Contains(err.Error(), "legacy Docker Registry v1 API"); got != tt.wantV1 {
+				t.Errorf("resolve() error = %v, want v1 explanation %v", err, tt.wantV1)
+			}
+		})
+	}
+}
+
+func TestPull_explainsOnlyResolveErrors(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+	// a registry answering the v1 ping, but whose blobs are missing
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/_ping":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/app/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	tests := map[string]bool{"v1": false, "missing": true}
+	for tag, wantV1 := range tests {
+		_, err := c.pull(context.Background(), u.Host+"/app:"+tag, memory.New(), pullOptions{})
+		if err == nil {
+			t.Fatalf("pull(%s) error = nil, want error", tag)
+		}
+		if got := strings.Contains(err.Error(), "legacy Docker Registry v1 API"); got != wantV1 {
+			t.Errorf("pull(%s) error = %v, want v1 explanation %v", tag, err, wantV1)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// lockfile records the digests the references resolved to, so subsequent
+// runs pull the exact same content instead of resolving the tags again.
+type lockfile struct {
+	path   string
+	update bool
+
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func loadLockfile(path string, update bool) (*lockfile, error) {
+	l := &lockfile{
+		path:    path,
+		update:  update,
+		entries: make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return l, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.entries); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// lookup returns the locked digest of reference, if any.
+func (l *lockfile) lookup(reference string) (string, bool) {
+	if l == nil || l.update {
+		return "", false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	dgst, ok := l.entries[reference]
+	return dgst, ok
+}
+
+// record locks reference to dgst, and writes the lockfile when changed.
+func (l *lockfile) record(reference, dgst string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries[reference] == dgst {
+		return nil
+	}
+	l.entries[reference] = dgst
+
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(l.path, append(data, '\n'))
+}
+
+// writeFileAtomic writes data to a temporary file renamed to path, so readers
+// never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	fp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fp.Name())
+
+	if _, err := fp.Write(data); err != nil {
+		fp.Close()
+		return err
+	}
+	if err := fp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(fp.Name(), path)
+}
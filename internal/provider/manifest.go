@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// Media types of the Docker image manifest schema 2.
+const (
+	mediaTypeDockerManifest    = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerImageConfig = "application/vnd.docker.container.image.v1+json"
+)
+
+// mediaTypeEmptyJSON is the media type of the empty config descriptor of OCI
+// 1.1 artifacts, which replaced the scratch media type of the release
+// candidates.
+const mediaTypeEmptyJSON = "application/vnd.oci.empty.v1+json"
+
+// hasConfig reports whether config describes a real config, rather than the
+// empty config descriptor of artifacts without config.
+func hasConfig(config *ocispec.Descriptor) bool {
+	if config == nil || config.Digest == "" {
+		return false
+	}
+	switch {
+	case config.MediaType == mediaTypeEmptyJSON, config.MediaType == ocispec.MediaTypeScratch:
+		return false
+	case config.Digest == ocispec.ScratchDescriptor.Digest && config.Size == ocispec.ScratchDescriptor.Size:
+		return false
+	}
+	return true
+}
+
+// layerMediaTypes returns the distinct media types of layers, sorted.
+func layerMediaTypes(layers []ocispec.Descriptor) []string {
+	var mediaTypes []string
+	seen := make(map[string]bool)
+	for _, layer := range layers {
+		if !seen[layer.MediaType] {
+			seen[layer.MediaType] = true
+			mediaTypes = append(mediaTypes, layer.MediaType)
+		}
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
+
+// artifactSize returns the total size of the manifest described by desc, its
+// config and its layers.
+func artifactSize(desc ocispec.Descriptor, manifest ocispec.Manifest) int64 {
+	size := desc.Size + manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+// flattenLayers returns the media type, digest and size of layers.
+func flattenLayers(layers []ocispec.Descriptor) []any {
+	result := make([]any, 0, len(layers))
+	for _, layer := range layers {
+		result = append(result, map[string]any{
+			"media_type": layer.MediaType,
+			"digest":     layer.Digest.String(),
+			"size":       int(layer.Size),
+		})
+	}
+	return result
+}
+
+// fetchManifest fetches and parses the image manifest described by desc.
+func fetchManifest(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (ocispec.Manifest, error) {
+	var manifest ocispec.Manifest
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return manifest, err
+	}
+	err = json.Unmarshal(data, &manifest)
+	return manifest, err
+}
+
+// fetchPlatform returns the platform of the image described by desc, as
+// declared in its config. It returns nil when desc is not an image manifest.
+func fetchPlatform(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (*ocispec.Platform, error) {
+	if desc.MediaType != ocispec.MediaTypeImageManifest && desc.MediaType != mediaTypeDockerManifest {
+		return nil, nil
+	}
+
+	manifest, err := fetchManifest(ctx, fetcher, desc)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Config.MediaType != ocispec.MediaTypeImageConfig && manifest.Config.MediaType != mediaTypeDockerImageConfig {
+		return nil, nil
+	}
+
+	config, err := fetchImageConfig(ctx, fetcher, manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+	if config.OS == "" && config.Architecture == "" {
+		return nil, nil
+	}
+
+	return &ocispec.Platform{
+		OS:           config.OS,
+		Architecture: config.Architecture,
+		Variant:      config.Variant,
+	}, nil
+}
+
+// mergeConfigLabels returns annotations merged with the labels of the image
+// config described by config. The annotations take precedence over labels
+// with the same key. Configs which are not image configs have no labels.
+func mergeConfigLabels(ctx context.Context, fetcher content.Fetcher, config *ocispec.Descriptor, annotations map[string]string) (map[string]string, error) {
+	if config == nil || (config.MediaType != ocispec.MediaTypeImageConfig && config.MediaType != mediaTypeDockerImageConfig) {
+		return annotations, nil
+	}
+
+	image, err := fetchImageConfig(ctx, fetcher, *config)
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]string, len(image.Config.Labels)+len(annotations))
+	for k, v := range image.Config.Labels {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// fetchImageConfig fetches and parses the image config described by desc.
+func fetchImageConfig(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) (ocispec.Image, error) {
+	var config ocispec.Image
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(data, &config)
+	return config, err
+}
+
+// writeConfig writes the config blob described by desc to path as is. The
+// config is handled as an opaque blob, so configs of custom media types the
+// provider does not know can be consumed by other tools.
+func writeConfig(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor, path string) error {
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// pushJSON pushes v as JSON blob to store, returning its descriptor.
+func pushJSON(t *testing.T, store *memory.Store, mediaType string, v any) ocispec.Descriptor {
+	t.Helper()
+	blob, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal("json.Marshal() error =", err)
+	}
+	return pushBlob(t, store, mediaType, blob)
+}
+
+// pushBlob pushes blob to store, returning its descriptor.
+func pushBlob(t *testing.T, store *memory.Store, mediaType string, blob []byte) ocispec.Descriptor {
+	t.Helper()
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+	if err := store.Push(context.Background(), desc, bytes.NewReader(blob)); err != nil {
+		t.Fatal("Store.Push() error =", err)
+	}
+	return desc
+}
+
+func pushManifest(t *testing.T, store *memory.Store, config ocispec.Descriptor, layers ...ocispec.Descriptor) ocispec.Descriptor {
+	t.Helper()
+	return pushJSON(t, store, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    layers,
+	})
+}
+
+func TestFetchPlatform(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	image := pushManifest(t, store, pushJSON(t, store, ocispec.MediaTypeImageConfig, ocispec.Image{
+		Platform: ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"},
+	}))
+	artifact := pushManifest(t, store, pushBlob(t, store, "application/vnd.test.config", []byte("{}")))
+
+	got, err := fetchPlatform(ctx, store, image)
+	if err != nil {
+		t.Fatal("fetchPlatform() error =", err)
+	}
+	want := &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fetchPlatform() = %v, want %v", got, want)
+	}
+
+	got, err = fetchPlatform(ctx, store, artifact)
+	if err != nil {
+		t.Fatal("fetchPlatform() error =", err)
+	}
+	if got != nil {
+		t.Errorf("fetchPlatform() = %v, want nil", got)
+	}
+}
+
+func TestHasConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ocispec.Descriptor
+		want   bool
+	}{
+		{"none", nil, false},
+		{"empty", &ocispec.Descriptor{MediaType: mediaTypeEmptyJSON, Digest: ocispec.ScratchDescriptor.Digest, Size: 2}, false},
+		{"scratch", &ocispec.ScratchDescriptor, false},
+		{"empty digest with custom media type", &ocispec.Descriptor{MediaType: "application/vnd.test.config", Digest: ocispec.ScratchDescriptor.Digest, Size: 2}, false},
+		{"image config", &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromString(`{"os":"linux"}`), Size: 14}, true},
+	}
+	for _, tt := range tests {
+		if got := hasConfig(tt.config); got != tt.want {
+			t.Errorf("hasConfig(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLayerMediaTypes(t *testing.T) {
+	layers := []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageLayerGzip},
+		{MediaType: "application/vnd.test.file"},
+		{MediaType: ocispec.MediaTypeImageLayerGzip},
+	}
+	want := []string{"application/vnd.oci.image.layer.v1.tar+gzip", "application/vnd.test.file"}
+	if got := layerMediaTypes(layers); !reflect.DeepEqual(got, want) {
+		t.Errorf("layerMediaTypes() = %v, want %v", got, want)
+	}
+	if got := layerMediaTypes(nil); got != nil {
+		t.Errorf("layerMediaTypes(nil) = %v, want nil", got)
+	}
+}
+
+func TestArtifactSize(t *testing.T) {
+	manifest := ocispec.Manifest{
+		Config: ocispec.Descriptor{Size: 2},
+		Layers: []ocispec.Descriptor{{Size: 100}, {Size: 50}},
+	}
+	if got := artifactSize(ocispec.Descriptor{Size: 300}, manifest); got != 452 {
+		t.Errorf("artifactSize() = %d, want 452", got)
+	}
+}
+
+func TestWriteConfig_customMediaType(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	config := []byte{0x00, 0x01, 0xfe, 0xff}
+	layer := pushBlob(t, store, "application/vnd.test.layer", []byte("hello"))
+	layer.Annotations = map[string]string{ocispec.AnnotationTitle: "hello.txt"}
+	root := pushManifest(t, store, pushBlob(t, store, "application/vnd.test.model.config.v1+binary", config), layer)
+	if err := store.Tag(ctx, root, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	dir := t.TempDir()
+	dst, err := file.New(dir)
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer dst.Close()
+
+	desc, err := oras.Copy(ctx, store, "v1", dst, "v1", oras.DefaultCopyOptions)
+	if err != nil {
+		t.Fatal("oras.Copy() error =", err)
+	}
+	if platform, err := fetchPlatform(ctx, dst, desc); err != nil || platform != nil {
+		t.Fatalf("fetchPlatform() = %v, %v, want nil", platform, err)
+	}
+
+	manifest, err := fetchManifest(ctx, dst, desc)
+	if err != nil {
+		t.Fatal("fetchManifest() error =", err)
+	}
+	path := filepath.Join(t.TempDir(), "config", "model.bin")
+	if err := writeConfig(ctx, dst, manifest.Config, path); err != nil {
+		t.Fatal("writeConfig() error =", err)
+	}
+	if got, err := os.ReadFile(path); err != nil || !bytes.Equal(got, config) {
+		t.Errorf("config = %v, %v, want %v", got, err, config)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "hello.txt")); err != nil || string(got) != "hello" {
+		t.Errorf("hello.txt = %q, %v, want %q", got, err, "hello")
+	}
+}
+
+func TestMergeConfigLabels(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	image := pushJSON(t, store, ocispec.MediaTypeImageConfig, ocispec.Image{
+		Config: ocispec.ImageConfig{Labels: map[string]string{"a": "label", "b": "label"}},
+	})
+	custom := pushBlob(t, store, "application/vnd.test.config", []byte(`{"config":{"Labels":{"a":"label"}}}`))
+	annotations := map[string]string{"b": "annotation", "c": "annotation"}
+
+	got, err := mergeConfigLabels(ctx, store, &image, annotations)
+	if err != nil {
+		t.Fatal("mergeConfigLabels() error =", err)
+	}
+	want := map[string]string{"a": "label", "b": "annotation", "c": "annotation"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeConfigLabels() = %v, want %v", got, want)
+	}
+
+	if got, err = mergeConfigLabels(ctx, store, &custom, annotations); err != nil || !reflect.DeepEqual(got, annotations) {
+		t.Errorf("mergeConfigLabels(custom) = %v, %v, want %v", got, err, annotations)
+	}
+}
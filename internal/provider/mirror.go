@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"path"
+
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// registryMirror is a pull-through cache serving the repositories of a
+// registry, optionally below a path prefix.
+type registryMirror struct {
+	registry   string
+	endpoint   string
+	pathPrefix string
+}
+
+// mirrorFor returns the mirror configured for the registry host, if any.
+func (c *clients) mirrorFor(host string) (registryMirror, bool) {
+	for _, mirror := range c.mirrors {
+		if mirror.registry == host {
+			return mirror, true
+		}
+	}
+	return registryMirror{}, false
+}
+
+// newReadRepository is like NewRepository, but reads the repository from the
+// mirror configured for its registry, if any. Anything writing to a repository
+// uses NewRepository, so pushes and tags always go to the origin.
+func (c *clients) newReadRepository(reference string) (*remote.Repository, error) {
+	repo, err := c.NewRepository(reference)
+	if err != nil {
+		return nil, err
+	}
+	if mirror, ok := c.mirrorFor(repo.Reference.Registry); ok {
+		if repo.Reference, err = mirror.route(repo.Reference); err != nil {
+			return nil, err
+		}
+		repo.PlainHTTP = c.usePlainHTTP(repo.Reference.Host())
+	}
+	return repo, nil
+}
+
+// route returns the reference of ref on the mirror.
+func (m registryMirror) route(ref registry.Reference) (registry.Reference, error) {
+	ref.Registry = m.endpoint
+	if m.pathPrefix != "" {
+		ref.Repository = path.Join(m.pathPrefix, ref.Repository)
+	}
+	if err := ref.ValidateRegistry(); err != nil {
+		return registry.Reference{}, err
+	}
+	if err := ref.ValidateRepository(); err != nil {
+		return registry.Reference{}, err
+	}
+	return ref, nil
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestNewReadRepository_mirror(t *testing.T) {
+	c := &clients{mirrors: []registryMirror{
+		{registry: "docker.io", endpoint: "artifactory.example.com", pathPrefix: "mirror/docker.io"},
+		{registry: "quay.io", endpoint: "quay-cache.example.com"},
+	}}
+
+	tests := map[string]string{
+		"docker.io/library/ubuntu:22.04": "artifactory.example.com/mirror/docker.io/library/ubuntu:22.04",
+		"quay.io/org/app:1.0":            "quay-cache.example.com/org/app:1.0",
+		"ghcr.io/org/app:1.0":            "ghcr.io/org/app:1.0",
+	}
+	for reference, want := range tests {
+		repo, err := c.newReadRepository(reference)
+		if err != nil {
+			t.Errorf("newReadRepository(%s) error = %v", reference, err)
+			continue
+		}
+		if got := repo.Reference.String(); got != want {
+			t.Errorf("newReadRepository(%s).Reference = %s, want %s", reference, got, want)
+		}
+
+		// repositories written to are never mirrored
+		repo, err = c.NewRepository(reference)
+		if err != nil {
+			t.Errorf("NewRepository(%s) error = %v", reference, err)
+			continue
+		}
+		if got := repo.Reference.String(); got != reference {
+			t.Errorf("NewRepository(%s).Reference = %s, want %s", reference, got, reference)
+		}
+	}
+}
+
+func TestNewReadRepository_mirrorCredentials(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "mirror-user" || password != "mirror-pass" {
+			w.Header().Set("Www-Authenticate", `Basic realm="mirror"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/remote/docker.io/library/app/manifests/1.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+		_, _ = w.Write(manifest)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srv.Certificate())
+	client, err := authClient(clientConfig{
+		network: "tcp",
+		rootCAs: rootCAs,
+		creds: map[string]auth.Credential{
+			u.Host:      {Username: "mirror-user", Password: "mirror-pass"},
+			"docker.io": {Username: "origin-user", Password: "origin-pass"},
+		},
+	})
+	if err != nil {
+		t.Fatal("authClient() error =", err)
+	}
+
+	c := &clients{
+		client:  client,
+		mirrors: []registryMirror{{registry: "docker.io", endpoint: u.Host, pathPrefix: "remote/docker.io"}},
+	}
+	repo, err := c.newReadRepository("docker.io/library/app:1.0")
+	if err != nil {
+		t.Fatal("newReadRepository() error =", err)
+	}
+
+	desc, err := repo.Resolve(context.Background(), repo.Reference.Reference)
+	if err != nil {
+		t.Fatal("Resolve() error =", err)
+	}
+	if want := digest.FromBytes(manifest); desc.Digest != want {
+		t.Errorf("Resolve() digest = %s, want %s", desc.Digest, want)
+	}
+}
+
+func TestMirror_writesGoToOrigin(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+	manifestDigest := digest.FromBytes(manifest)
+
+	mirror := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("mirror received %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer mirror.Close()
+
+	var mu sync.Mutex
+	var puts []string
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v2/org/app/manifests/"):
+			mu.Lock()
+			puts = append(puts, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/org/app/blobs/uploads/":
+			w.Header().Set("Location", "/v2/org/app/blobs/uploads/1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/org/app/blobs/uploads/1":
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/v2/org/app/manifests/v1" || r.URL.Path == "/v2/org/app/manifests/"+manifestDigest.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			if r.Method == http.MethodHead {
+				w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+				return
+			}
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer origin.Close()
+
+	originURL, _ := url.Parse(origin.URL)
+	mirrorURL, _ := url.Parse(mirror.URL)
+	c := &clients{
+		client:  &auth.Client{Client: origin.Client()},
+		mirrors: []registryMirror{{registry: originURL.Host, endpoint: mirrorURL.Host}},
+	}
+
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	push := schema.TestResourceDataRaw(t, resourceOrasPush().Schema, map[string]any{
+		"reference": originURL.Host + "/org/app:v2",
+		"files":     []any{map[string]any{"path": path}},
+	})
+	if diags := resourceOrasPushCreate(context.Background(), push, c); diags.HasError() {
+		t.Fatalf("resourceOrasPushCreate() = %v", diags)
+	}
+
+	tag := schema.TestResourceDataRaw(t, resourceOrasTag().Schema, map[string]any{
+		"reference": originURL.Host + "/org/app:v1",
+		"tag":       "stable",
+	})
+	if diags := resourceOrasTagCreate(context.Background(), tag, c); diags.HasError() {
+		t.Fatalf("resourceOrasTagCreate() = %v", diags)
+	}
+	if want := originURL.Host + "/org/app:stable"; tag.Id() != want {
+		t.Errorf("oras_tag ID = %s, want %s", tag.Id(), want)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"/v2/org/app/manifests/v2", "/v2/org/app/manifests/stable"}
+	if strings.Join(puts, ",") != strings.Join(want, ",") {
+		t.Errorf("origin manifest uploads = %v, want %v", puts, want)
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// packConfig is the config blob of a packed manifest.
+type packConfig struct {
+	mediaType string
+	content   []byte
+}
+
+// packConfigSchema returns the schema of the config of a packed manifest.
+func packConfigSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Description: "The config of the manifest, e.g. the config of a Helm chart. " +
+			"By default the config is the OCI empty config, an empty JSON object with media type `" + mediaTypeEmptyJSON + "`.",
+		Type:     schema.TypeList,
+		Optional: true,
+		ForceNew: forceNew,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"media_type": {
+					Description: "The media type of the config. Defaults to the OCI empty config `" + mediaTypeEmptyJSON + "`, " +
+						"in which case `artifact_type` is required.",
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: forceNew,
+					Default:  mediaTypeEmptyJSON,
+				},
+				"content": {
+					Description:   "The content of the config. Defaults to an empty JSON object, `{}`.",
+					Type:          schema.TypeString,
+					Optional:      true,
+					ForceNew:      forceNew,
+					ConflictsWith: []string{"config.0.content_base64"},
+				},
+				"content_base64": {
+					Description:   "The base64 encoded content of the config, for binary configs.",
+					Type:          schema.TypeString,
+					Optional:      true,
+					ForceNew:      forceNew,
+					ValidateFunc:  validation.StringIsBase64,
+					ConflictsWith: []string{"config.0.content"},
+				},
+			},
+		},
+	}
+}
+
+func expandPackConfig(l []any) (*packConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	config := &packConfig{mediaType: mediaTypeEmptyJSON, content: []byte("{}")}
+	m, ok := l[0].(map[string]any)
+	if !ok {
+		return config, nil
+	}
+	config.mediaType = m["media_type"].(string)
+	if v := m["content"].(string); v != "" {
+		config.content = []byte(v)
+	}
+	if v := m["content_base64"].(string); v != "" {
+		data, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode config: %w", err)
+		}
+		config.content = data
+	}
+	return config, nil
+}
+
+// packArtifact packs layers into an image manifest, pushing the config and
+// the manifest to pusher. Unlike oras.Pack, no created annotation is added
+// unless it is part of annotations, so packing the same layers always results
+// in the same manifest digest. Without config, the config is the OCI empty
+// config. The artifact type of manifests with the empty config is required by
+// OCI 1.1, it defaults to the unknown artifact type of oras when packing the
+// default config and must be set with an explicit empty config.
+func packArtifact(ctx context.Context, pusher content.Pusher, artifactType string, config *packConfig, layers []ocispec.Descriptor, annotations map[string]string) (ocispec.Descriptor, error) {
+	if config == nil {
+		if artifactType == "" {
+			artifactType = oras.MediaTypeUnknownArtifact
+		}
+		config = &packConfig{mediaType: mediaTypeEmptyJSON, content: []byte("{}")}
+	}
+	if config.mediaType == mediaTypeEmptyJSON && artifactType == "" {
+		return ocispec.Descriptor{}, fmt.Errorf("an artifact type is required with the empty config %s", mediaTypeEmptyJSON)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	if layers == nil {
+		layers = []ocispec.Descriptor{}
+	}
+
+	configDesc := content.NewDescriptorFromBytes(config.mediaType, config.content)
+	if err := pusher.Push(ctx, configDesc, bytes.NewReader(config.content)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push config: %w", err)
+	}
+
+	manifest := ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: artifactType,
+		Config:       configDesc,
+		Layers:       layers,
+		Annotations:  annotations,
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err := pusher.Push(ctx, desc, bytes.NewReader(manifestJSON)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push manifest: %w", err)
+	}
+	return desc, nil
+}
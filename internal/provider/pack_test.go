@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestPackArtifact_computedDigestMatchesPush(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("key: value\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	annotations := map[string]string{"org.opencontainers.image.source": "https://github.com/me/thing"}
+
+	store, err := file.New("")
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+	pushed, err := addPushFiles(ctx, store, []pushFile{{path: path, mediaType: ocispec.MediaTypeImageLayer}})
+	if err != nil {
+		t.Fatal("addPushFiles() error =", err)
+	}
+	pushedDesc, err := packArtifact(ctx, store, "application/vnd.test", nil, pushed, annotations)
+	if err != nil {
+		t.Fatal("packArtifact() error =", err)
+	}
+
+	for name, args := range map[string][3]string{
+		"path":    {path, "", ""},
+		"content": {"", "key: value\n", "config.yaml"},
+	} {
+		layer, err := computeLayer(args[0], args[1], args[2], ocispec.MediaTypeImageLayer)
+		if err != nil {
+			t.Fatalf("computeLayer(%s) error = %v", name, err)
+		}
+		desc, err := packArtifact(ctx, memory.New(), "application/vnd.test", nil, []ocispec.Descriptor{layer}, annotations)
+		if err != nil {
+			t.Fatal("packArtifact() error =", err)
+		}
+		if desc.Digest != pushedDesc.Digest {
+			t.Errorf("computed digest from %s = %s, want %s", name, desc.Digest, pushedDesc.Digest)
+		}
+	}
+}
+
+func TestComputeLayer_invalid(t *testing.T) {
+	if _, err := computeLayer("file", "content", "", ocispec.MediaTypeImageLayer); err == nil {
+		t.Error("expected error with both path and content")
+	}
+	if _, err := computeLayer("", "content", "", ocispec.MediaTypeImageLayer); err == nil {
+		t.Error("expected error with content but without name")
+	}
+}
+
+func TestPackArtifact_config(t *testing.T) {
+	ctx := context.Background()
+	layers := []ocispec.Descriptor{}
+	helm := []any{map[string]any{"media_type": "application/vnd.cncf.helm.config.v1+json", "content": `{"name":"chart"}`, "content_base64": ""}}
+
+	tests := []struct {
+		name             string
+		artifactType     string
+		config           []any
+		wantErr          bool
+		wantMediaType    string
+		wantContent      string
+		wantArtifactType string
+	}{
+		{name: "default", artifactType: "application/vnd.test", wantMediaType: mediaTypeEmptyJSON, wantContent: "{}", wantArtifactType: "application/vnd.test"},
+		{name: "default without artifact type", wantMediaType: mediaTypeEmptyJSON, wantContent: "{}", wantArtifactType: oras.MediaTypeUnknownArtifact},
+		{name: "empty block", artifactType: "application/vnd.test", config: []any{nil}, wantMediaType: mediaTypeEmptyJSON, wantContent: "{}", wantArtifactType: "application/vnd.test"},
+		{name: "empty block without artifact type", config: []any{nil}, wantErr: true},
+		{
+			name:             "helm",
+			artifactType:     "application/vnd.test",
+			config:           helm,
+			wantMediaType:    "application/vnd.cncf.helm.config.v1+json",
+			wantContent:      `{"name":"chart"}`,
+			wantArtifactType: "application/vnd.test",
+		},
+		{
+			name:          "helm without artifact type",
+			config:        helm,
+			wantMediaType: "application/vnd.cncf.helm.config.v1+json",
+			wantContent:   `{"name":"chart"}`,
+		},
+		{
+			name:             "base64",
+			artifactType:     "application/vnd.test",
+			config:           []any{map[string]any{"media_type": "application/vnd.test.config", "content": "", "content_base64": "AAH/"}},
+			wantMediaType:    "application/vnd.test.config",
+			wantContent:      "\x00\x01\xff",
+			wantArtifactType: "application/vnd.test",
+		},
+	}
+	for _, tt := range tests {
+		config, err := expandPackConfig(tt.config)
+		if err != nil {
+			t.Fatalf("expandPackConfig(%s) error = %v", tt.name, err)
+		}
+		store := memory.New()
+		desc, err := packArtifact(ctx, store, tt.artifactType, config, layers, nil)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("packArtifact(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		manifest, err := fetchManifest(ctx, store, desc)
+		if err != nil {
+			t.Fatalf("fetchManifest(%s) error = %v", tt.name, err)
+		}
+		if manifest.Config.MediaType != tt.wantMediaType || manifest.ArtifactType != tt.wantArtifactType {
+			t.Errorf("packArtifact(%s) config media type = %s, artifact type = %s, want %s, %s",
+				tt.name, manifest.Config.MediaType, manifest.ArtifactType, tt.wantMediaType, tt.wantArtifactType)
+		}
+		data, err := content.FetchAll(ctx, store, manifest.Config)
+		if err != nil {
+			t.Fatalf("content.FetchAll(%s) error = %v", tt.name, err)
+		}
+		if string(data) != tt.wantContent {
+			t.Errorf("packArtifact(%s) config = %q, want %q", tt.name, data, tt.wantContent)
+		}
+	}
+}
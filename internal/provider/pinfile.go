@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Formats of the pin file.
+const (
+	pinFormatJSON = "json"
+	pinFormatYAML = "yaml"
+	pinFormatTOML = "toml"
+)
+
+// pinFile exports the digests the pulled references resolved to, in a format
+// readable by other tools. Unlike the lockfile it is never read back, it is
+// rewritten with the references pulled by the current run.
+type pinFile struct {
+	path   string
+	format string
+
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newPinFile(path, format string) *pinFile {
+	return &pinFile{path: path, format: format, entries: make(map[string]string)}
+}
+
+// record pins reference to dgst, and writes the pin file when changed.
+func (p *pinFile) record(reference, dgst string) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.entries[reference] == dgst {
+		return nil
+	}
+	p.entries[reference] = dgst
+
+	data, err := formatPins(p.entries, p.format)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p.path, data)
+}
+
+// formatPins renders the pins sorted by reference, as a JSON object, or as
+// `key: value` or `key = value` lines for YAML and TOML. The keys and values
+// are quoted as JSON strings, which are valid strings in both YAML and TOML.
+func formatPins(entries map[string]string, format string) ([]byte, error) {
+	if format == pinFormatJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var separator string
+	switch format {
+	case pinFormatYAML:
+		separator = ": "
+	case pinFormatTOML:
+		separator = " = "
+	default:
+		return nil, fmt.Errorf("unsupported pin file format '%s'", format)
+	}
+
+	references := make([]string, 0, len(entries))
+	for reference := range entries {
+		references = append(references, reference)
+	}
+	sort.Strings(references)
+
+	var buf bytes.Buffer
+	for _, reference := range references {
+		key, _ := json.Marshal(reference)
+		value, _ := json.Marshal(entries[reference])
+		buf.Write(key)
+		buf.WriteString(separator)
+		buf.Write(value)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPinFile(t *testing.T) {
+	const (
+		app   = "ghcr.io/org/app:v1"
+		tools = "ghcr.io/org/tools:latest"
+		dgst  = "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	)
+
+	tests := map[string]string{
+		pinFormatJSON: "{\n  \"" + app + "\": \"" + dgst + "\",\n  \"" + tools + "\": \"" + dgst + "\"\n}\n",
+		pinFormatYAML: "\"" + app + "\": \"" + dgst + "\"\n\"" + tools + "\": \"" + dgst + "\"\n",
+		pinFormatTOML: "\"" + app + "\" = \"" + dgst + "\"\n\"" + tools + "\" = \"" + dgst + "\"\n",
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pins")
+			p := newPinFile(path, format)
+			for _, reference := range []string{tools, app, tools} {
+				if err := p.record(reference, dgst); err != nil {
+					t.Fatal("record() error =", err)
+				}
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("pin file = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// platformSchema returns the schema of the platform selecting a manifest of a
+// multi-arch index.
+func platformSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "When the artifact is a multi-arch index, select the manifest of this platform. " +
+			"Fails when the index has no manifest for the platform.",
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"index_annotations"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"os": {
+					Description: "The operating system, e.g. `linux`.",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"architecture": {
+					Description: "The CPU architecture, e.g. `amd64` or `arm64`.",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"variant": {
+					Description: "The variant of the CPU architecture, e.g. `v7` for `arm`.",
+					Type:        schema.TypeString,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+func expandPlatform(l []any) *ocispec.Platform {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	m := l[0].(map[string]any)
+	return &ocispec.Platform{
+		OS:           m["os"].(string),
+		Architecture: m["architecture"].(string),
+		Variant:      m["variant"].(string),
+	}
+}
+
+// formatPlatform formats p as `os/architecture[/variant]`.
+func formatPlatform(p *ocispec.Platform) string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// withTargetPlatform makes the copy select the manifest of platform p from an
+// index, failing with a clear error when the index has none.
+func withTargetPlatform(opts *oras.CopyOptions, reference string, p *ocispec.Platform) {
+	if p == nil {
+		return
+	}
+	opts.WithTargetPlatform(p)
+	selectManifest := opts.MapRoot
+	opts.MapRoot = func(ctx context.Context, src content.ReadOnlyStorage, root ocispec.Descriptor) (ocispec.Descriptor, error) {
+		desc, err := selectManifest(ctx, src, root)
+		if errors.Is(err, errdef.ErrNotFound) {
+			return ocispec.Descriptor{}, fmt.Errorf("%s has no manifest for platform %s: %w", reference, formatPlatform(p), err)
+		}
+		return desc, err
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestWithTargetPlatform(t *testing.T) {
+	src := memory.New()
+	ctx := context.Background()
+
+	var manifests []ocispec.Descriptor
+	for _, platform := range []ocispec.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm", Variant: "v7"},
+	} {
+		platform := platform
+		desc := pushManifest(t, src, pushJSON(t, src, ocispec.MediaTypeImageConfig, ocispec.Image{Platform: platform}))
+		desc.Platform = &platform
+		manifests = append(manifests, desc)
+	}
+	index := pushJSON(t, src, ocispec.MediaTypeImageIndex, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+	if err := src.Tag(ctx, index, "v1"); err != nil {
+		t.Fatal("Store.Tag() error =", err)
+	}
+
+	copyWith := func(p *ocispec.Platform) (ocispec.Descriptor, error) {
+		opts := oras.DefaultCopyOptions
+		withTargetPlatform(&opts, "app:v1", p)
+		return oras.Copy(ctx, src, "v1", memory.New(), "v1", opts)
+	}
+
+	got, err := copyWith(&ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+	if err != nil {
+		t.Fatal("oras.Copy() error =", err)
+	}
+	if got.Digest != manifests[1].Digest {
+		t.Errorf("oras.Copy() = %s, want %s", got.Digest, manifests[1].Digest)
+	}
+
+	_, err = copyWith(&ocispec.Platform{OS: "linux", Architecture: "arm64"})
+	if err == nil || !strings.Contains(err.Error(), "app:v1 has no manifest for platform linux/arm64") {
+		t.Errorf("oras.Copy() error = %v, want missing platform", err)
+	}
+
+	if got, err = copyWith(nil); err != nil || got.Digest != index.Digest {
+		t.Errorf("oras.Copy() = %s, %v, want the index %s", got.Digest, err, index.Digest)
+	}
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/singleflight"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+)
+
+// prefetchConcurrency is the maximum number of child manifests prefetched at
+// once for a single copy.
+const prefetchConcurrency = 4
+
+// prefetchTarget fetches the child manifests of indexes ahead of the copy, so
+// resolving nested indexes overlaps with the download of the blobs. The
+// manifests are kept in memory until the copy reads them, and fetched once
+// even when the copy reads them while they are being prefetched. When the
+// source is cached, the cache is populated by the source itself.
+type prefetchTarget struct {
+	oras.ReadOnlyTarget
+	maxBytes int64
+
+	group     singleflight.Group
+	mu        sync.Mutex
+	manifests map[digest.Digest][]byte
+	sem       chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newPrefetchTarget(src oras.ReadOnlyTarget, maxBytes int64) *prefetchTarget {
+	return &prefetchTarget{
+		ReadOnlyTarget: src,
+		maxBytes:       maxBytes,
+		manifests:      make(map[digest.Digest][]byte),
+		sem:            make(chan struct{}, prefetchConcurrency),
+	}
+}
+
+func (t *prefetchTarget) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	if !isManifest(target) {
+		return t.ReadOnlyTarget.Fetch(ctx, target)
+	}
+	data, err := t.fetchManifest(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (t *prefetchTarget) fetchManifest(ctx context.Context, target ocispec.Descriptor) ([]byte, error) {
+	v, err, _ := t.group.Do(target.Digest.String(), func() (any, error) {
+		t.mu.Lock()
+		data, ok := t.manifests[target.Digest]
+		t.mu.Unlock()
+		if ok {
+			return data, nil
+		}
+
+		if t.maxBytes > 0 && target.Size > t.maxBytes {
+			return nil, fmt.Errorf("manifest %s of %d bytes exceeds the maximum of %d bytes", target.Digest, target.Size, t.maxBytes)
+		}
+		data, err := content.FetchAll(ctx, t.ReadOnlyTarget, target)
+		if err != nil {
+			return nil, err
+		}
+
+		t.mu.Lock()
+		t.manifests[target.Digest] = data
+		t.mu.Unlock()
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// findSuccessors returns the successors of desc, prefetching them in the
+// background when desc is an index.
+func (t *prefetchTarget) findSuccessors(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	successors, err := content.Successors(ctx, fetcher, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if isIndex(desc) {
+		for _, successor := range successors {
+			if isManifest(successor) {
+				t.prefetch(ctx, successor)
+			}
+		}
+	}
+	return successors, nil
+}
+
+func (t *prefetchTarget) prefetch(ctx context.Context, target ocispec.Descriptor) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		select {
+		case t.sem <- struct{}{}:
+			defer func() { <-t.sem }()
+		case <-ctx.Done():
+			return
+		}
+		// errors are ignored, the copy fetches the manifest again and reports them
+		_, _ = t.fetchManifest(ctx, target)
+	}()
+}
+
+// wait waits for the prefetches in progress.
+func (t *prefetchTarget) wait() {
+	t.wg.Wait()
+}
+
+func isIndex(desc ocispec.Descriptor) bool {
+	return desc.MediaType == ocispec.MediaTypeImageIndex || desc.MediaType == mediaTypeDockerManifestList
+}
+
+func isManifest(desc ocispec.Descriptor) bool {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList,
+		ocispec.MediaTypeImageManifest, mediaTypeDockerManifest:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// countingTarget counts the fetches of each digest from the target.
+type countingTarget struct {
+	oras.ReadOnlyTarget
+	mu      sync.Mutex
+	fetches map[digest.Digest]int
+}
+
+func (t *countingTarget) Fetch(ctx context.Context, target ocispec.Descriptor) (io.ReadCloser, error) {
+	t.mu.Lock()
+	t.fetches[target.Digest]++
+	t.mu.Unlock()
+	return t.ReadOnlyTarget.Fetch(ctx, target)
+}
+
+func TestPrefetchTarget(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	config := pushBlob(t, store, ocispec.MediaTypeImageConfig, []byte("{}"))
+	var manifests []ocispec.Descriptor
+	for _, layer := range []string{"amd64", "arm64"} {
+		manifests = append(manifests, pushManifest(t, store, config, pushBlob(t, store, ocispec.MediaTypeImageLayer, []byte(layer))))
+	}
+	index := pushJSON(t, store, ocispec.MediaTypeImageIndex, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+	if err := store.Tag(ctx, index, "v1"); err != nil {
+		t.Fatal("Tag() error =", err)
+	}
+
+	src := &countingTarget{ReadOnlyTarget: store, fetches: make(map[digest.Digest]int)}
+	p := newPrefetchTarget(src, 0)
+	opts := oras.DefaultCopyOptions
+	opts.FindSuccessors = p.findSuccessors
+
+	dst := memory.New()
+	desc, err := oras.Copy(ctx, p, "v1", dst, "v1", opts)
+	p.wait()
+	if err != nil {
+		t.Fatal("Copy() error =", err)
+	}
+	if desc.Digest != index.Digest {
+		t.Errorf("Copy() = %s, want %s", desc.Digest, index.Digest)
+	}
+
+	for _, m := range append(manifests, index) {
+		if exists, err := dst.Exists(ctx, m); err != nil || !exists {
+			t.Errorf("manifest %s not copied", m.Digest)
+		}
+		if n := src.fetches[m.Digest]; n != 1 {
+			t.Errorf("manifest %s fetched %d times from the source, want 1", m.Digest, n)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// mediaTypeInToto is the artifact type of in-toto attestations.
+const mediaTypeInToto = "application/vnd.in-toto+json"
+
+// provenanceSchema returns the schema of the verify_provenance block.
+func provenanceSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Verify the artifact against its SLSA provenance attestation, attached as referrer, failing when it is missing, " +
+			"was not produced by the expected builder or does not cover the pulled digest. The artifact is verified before writing any file to `output_path`. The signature of the attestation is not verified.",
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"builder_id": {
+					Description: "The expected builder identity, e.g. `https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v1.9.0`.",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"artifact_type": {
+					Description: "The artifact type of the attestation referrer.",
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     mediaTypeInToto,
+				},
+			},
+		},
+	}
+}
+
+// errNoProvenance is returned when no provenance attestation is attached to an
+// artifact.
+var errNoProvenance = errors.New("no provenance attestation")
+
+// provenanceStatement is the part of an in-toto statement with a SLSA
+// provenance predicate, either v0.2 or v1, needed for the verification.
+type provenanceStatement struct {
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	Predicate struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		RunDetails struct {
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+		} `json:"runDetails"`
+	} `json:"predicate"`
+}
+
+func (s *provenanceStatement) builderID() string {
+	if s.Predicate.RunDetails.Builder.ID != "" {
+		return s.Predicate.RunDetails.Builder.ID
+	}
+	return s.Predicate.Builder.ID
+}
+
+// covers reports whether the subject of the statement includes desc.
+func (s *provenanceStatement) covers(desc ocispec.Descriptor) bool {
+	for _, subject := range s.Subject {
+		if subject.Digest[desc.Digest.Algorithm().String()] == desc.Digest.Encoded() {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyProvenance verifies the provenance attestation attached to subject was
+// produced by builderID and covers subject. The error wraps errNoProvenance
+// when there is no attestation.
+func verifyProvenance(ctx context.Context, repo *remote.Repository, subject ocispec.Descriptor, artifactType, builderID string) error {
+	referrer, err := findReferrer(ctx, repo, subject, artifactType)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return fmt.Errorf("%w with artifact type %s is attached to %s", errNoProvenance, artifactType, subject.Digest)
+	}
+	if err != nil {
+		return err
+	}
+
+	manifest, err := fetchManifest(ctx, repo, referrer)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, layer := range manifest.Layers {
+		data, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return err
+		}
+		statement, err := parseProvenance(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("layer %s: %w", layer.Digest, err))
+			continue
+		}
+		if err := checkProvenance(statement, subject, builderID); err != nil {
+			errs = append(errs, fmt.Errorf("attestation %s: %w", referrer.Digest, err))
+			continue
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("%w: attestation %s has no layers", errNoProvenance, referrer.Digest)
+	}
+	return errors.Join(errs...)
+}
+
+// parseProvenance parses an in-toto statement with a SLSA provenance
+// predicate, optionally wrapped in a DSSE envelope.
+func parseProvenance(data []byte) (*provenanceStatement, error) {
+	var envelope struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid attestation: %w", err)
+	}
+	if envelope.Payload != "" {
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DSSE payload: %w", err)
+		}
+		data = payload
+	}
+
+	var statement provenanceStatement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return nil, fmt.Errorf("invalid in-toto statement: %w", err)
+	}
+	if !strings.HasPrefix(statement.PredicateType, "https://slsa.dev/provenance/") {
+		return nil, fmt.Errorf("predicate type %q is not SLSA provenance", statement.PredicateType)
+	}
+	return &statement, nil
+}
+
+func checkProvenance(statement *provenanceStatement, subject ocispec.Descriptor, builderID string) error {
+	if got := statement.builderID(); got != builderID {
+		return fmt.Errorf("built by %q, expected %q", got, builderID)
+	}
+	if !statement.covers(subject) {
+		return fmt.Errorf("does not cover %s", subject.Digest)
+	}
+	return nil
+}
+
+// provenanceDiagnostics returns the diagnostics for an error returned by
+// verifyProvenance, separating missing provenance from a mismatch.
+func provenanceDiagnostics(reference string, err error) diag.Diagnostics {
+	if errors.Is(err, errNoProvenance) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("No provenance found for %s", reference),
+			Detail:   err.Error(),
+		}}
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Provenance verification of %s failed", reference),
+		Detail:   err.Error(),
+	}}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestProvenance(t *testing.T) {
+	const builderID = "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml@refs/tags/v1.9.0"
+	subject := ocispec.Descriptor{Digest: digest.FromString("manifest")}
+
+	v02 := fmt.Sprintf(`{
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"subject": [{"name": "ghcr.io/test/artifact", "digest": {"sha256": %q}}],
+		"predicate": {"builder": {"id": %q}}
+	}`, subject.Digest.Encoded(), builderID)
+	v1 := fmt.Sprintf(`{
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"subject": [{"name": "ghcr.io/test/artifact", "digest": {"sha256": %q}}],
+		"predicate": {"runDetails": {"builder": {"id": %q}}}
+	}`, subject.Digest.Encoded(), builderID)
+	dsse := fmt.Sprintf(`{"payloadType": "application/vnd.in-toto+json", "payload": %q, "signatures": []}`,
+		base64.StdEncoding.EncodeToString([]byte(v1)))
+	otherSubject := fmt.Sprintf(`{
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"subject": [{"name": "ghcr.io/test/artifact", "digest": {"sha256": %q}}],
+		"predicate": {"runDetails": {"builder": {"id": %q}}}
+	}`, digest.FromString("other").Encoded(), builderID)
+
+	tests := []struct {
+		name          string
+		attestation   string
+		builderID     string
+		wantParseErr  bool
+		wantVerifyErr bool
+	}{
+		{name: "v0.2", attestation: v02, builderID: builderID},
+		{name: "v1", attestation: v1, builderID: builderID},
+		{name: "dsse envelope", attestation: dsse, builderID: builderID},
+		{name: "other builder", attestation: v1, builderID: "https://example.com/builder", wantVerifyErr: true},
+		{name: "other subject", attestation: otherSubject, builderID: builderID, wantVerifyErr: true},
+		{name: "not provenance", attestation: `{"predicateType": "https://spdx.dev/Document"}`, wantParseErr: true},
+		{name: "invalid", attestation: `{`, wantParseErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := parseProvenance([]byte(tt.attestation))
+			if (err != nil) != tt.wantParseErr {
+				t.Fatalf("parseProvenance() error = %v, wantErr %v", err, tt.wantParseErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := checkProvenance(statement, subject, tt.builderID); (err != nil) != tt.wantVerifyErr {
+				t.Errorf("checkProvenance() error = %v, wantErr %v", err, tt.wantVerifyErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -9,14 +12,21 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jsiebens/terraform-provider-oras/internal/cache"
 	"github.com/mitchellh/go-homedir"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/sync/semaphore"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"os"
+	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -54,6 +64,13 @@ func New(version string) func() *schema.Provider {
 								Description: "Password for the registry.",
 							},
 
+							"refresh_token": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Sensitive:   true,
+								Description: "Identity token exchanged for access tokens with the registry, e.g. the token of `az acr login --expose-token`, instead of a password.",
+							},
+
 							"config_file": {
 								Type:        schema.TypeString,
 								Optional:    true,
@@ -66,15 +83,488 @@ func New(version string) func() *schema.Provider {
 								Optional:    true,
 								Description: "Plain content of the docker json file for registry auth.",
 							},
+
+							"user_agent": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile(`[[:cntrl:]]`), "must not contain control characters"),
+								Description:  "Custom User-Agent sent to the registry, overriding the default one of the provider.",
+							},
+
+							"exec": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								Description: "Obtain the credentials by running a command each time they are needed, similar to the exec credential plugins of kubectl. " +
+									"The command must print either a token, or a JSON object with a `token` or a `username` and `password`, and optionally an RFC 3339 `expires_at` until which the credentials are reused.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"command": {
+											Type:         schema.TypeString,
+											Required:     true,
+											ValidateFunc: validation.StringIsNotEmpty,
+											Description:  "The command to run, either a path or a name looked up in the `PATH`.",
+										},
+										"args": {
+											Type:        schema.TypeList,
+											Optional:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+											Description: "The arguments of the command.",
+										},
+										"env": {
+											Type:        schema.TypeMap,
+											Optional:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+											Description: "Additional environment variables of the command.",
+										},
+									},
+								},
+							},
+
+							"github_oidc": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								Description: "Authenticate with the OIDC token of the GitHub Actions job, for keyless pulls in CI. " +
+									"Requires the `id-token: write` permission, the token is requested again when it expires.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"audience": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "The audience of the requested token. Defaults to the default audience of GitHub.",
+										},
+										"username": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "The username sent with the token. Defaults to the `GITHUB_ACTOR` environment variable.",
+										},
+									},
+								},
+							},
+
+							"ecr": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								Description: "Authenticate to an Amazon ECR registry with an authorization token obtained with the AWS SDK, " +
+									"using the standard AWS credential chain. The token is requested again when it expires.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"region": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "The AWS region of the registry. Defaults to the region in the registry address.",
+										},
+										"profile": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "The AWS shared configuration profile to use. Defaults to the default credential chain.",
+										},
+									},
+								},
+							},
+
+							"gcp": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+								Description: "Authenticate to Google Artifact Registry or Container Registry with an access token of the application default credentials, " +
+									"including workload identity and service account impersonation. Defaults to `false`.",
+							},
+
+							"keychain": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								Description: "Read the password from the keychain of the operating system, i.e. the macOS Keychain or the Windows Credential Manager. " +
+									"Not supported on other platforms.",
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"service": {
+											Type:        schema.TypeString,
+											Optional:    true,
+											Description: "The service of the keychain entry. Defaults to the hostname of the registry.",
+										},
+										"username": {
+											Type:        schema.TypeString,
+											Required:    true,
+											Description: "The account of the keychain entry, also used as the username for the registry.",
+										},
+									},
+								},
+							},
+
+							"anonymous": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+								Description: "Always access the registry anonymously, ignoring any other credential configured for it, " +
+									"e.g. when stale credentials in a docker config break pulls of public artifacts. Defaults to `false`.",
+							},
+
+							"raw_authorization": {
+								Type:      schema.TypeString,
+								Optional:  true,
+								Sensitive: true,
+								Description: "Verbatim value of the `Authorization` header sent with every request to the registry. " +
+									"This bypasses the regular credential and token challenge flow, hence tokens are never refreshed by the provider.",
+							},
+
+							"plain_http": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+								Description: "Connect to the registry over plain HTTP instead of HTTPS, e.g. for a local registry on `localhost:5000`. " +
+									"Also enabled when the `address` starts with `http://`. Defaults to `false`.",
+							},
+
+							"client_cert_file": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Path to the PEM encoded client certificate presented to the registry for mutual TLS. Requires a client key.",
+							},
+
+							"client_key_file": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Path to the PEM encoded private key of the client certificate.",
+							},
+
+							"client_cert_pem": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "PEM encoded client certificate presented to the registry for mutual TLS, instead of `client_cert_file`. Requires a client key.",
+							},
+
+							"client_key_pem": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Sensitive:   true,
+								Description: "PEM encoded private key of the client certificate, instead of `client_key_file`.",
+							},
+
+							"insecure_skip_verify": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  false,
+								Description: "Skip the verification of the TLS certificate of the registry, e.g. for a registry with a self-signed certificate. " +
+									"Only applies to this registry, other registries are always verified. Defaults to `false`.",
+							},
 						},
 					},
 				},
+
+				"reference_rewrite": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Description: "Regular expression replacements applied to every reference before it is parsed, to adapt the non-standard references of some registries. " +
+						"The rewrites are applied in order, each one to the result of the previous one.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"pattern": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringIsValidRegExp,
+								Description:  "The regular expression matching the parts of the reference to replace, in the syntax of Go's `regexp` package.",
+							},
+							"replacement": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "The replacement of the matches, in which `$1` or `${name}` refer to the submatches of the pattern.",
+							},
+						},
+					},
+				},
+
+				"default_registry": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ORAS_DEFAULT_REGISTRY", ""),
+					Description: "The registry host prefixed to references without a registry, e.g. `myrepo:tag` or `team/app:1.0`, for organizations with a single internal registry. " +
+						"A reference has no registry when its first path component contains no `.` or `:` and is not `localhost`. " +
+						"Applied after `reference_rewrite`. Can also be set with the `ORAS_DEFAULT_REGISTRY` environment variable. By default such references are rejected.",
+				},
+
+				"registry_mirror": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Description: "Pull-through caches the artifacts of a registry are read from instead, e.g. Artifactory or Nexus remote repositories. " +
+						"Blobs and manifests are still verified against their digests, which do not change when served by a mirror. Pushes and tags always go to the registry itself.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"registry": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "The registry host mirrored, e.g. `docker.io`.",
+							},
+							"endpoint": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "The host of the mirror, e.g. `artifactory.example.com`. Its credentials are read from the `registry_auth` block of this host.",
+							},
+							"path_prefix": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "The path prepended to the repositories on the mirror, e.g. `mirror/docker.io` to read `docker.io/library/ubuntu` from `artifactory.example.com/mirror/docker.io/library/ubuntu`.",
+							},
+						},
+					},
+				},
+
+				"network": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "tcp",
+					ValidateFunc: validation.StringInSlice([]string{"tcp", "tcp4", "tcp6"}, false),
+					Description:  "The network used to connect to registries, one of `tcp`, `tcp4` (IPv4 only) or `tcp6` (IPv6 only). Defaults to `tcp`.",
+				},
+
+				"max_manifest_size": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      4 * 1024 * 1024,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The maximum size in bytes of a manifest fetched from a registry, larger manifests are rejected before being parsed. Defaults to `4194304` (4 MiB).",
+				},
+
+				"accept_language": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Value of the `Accept-Language` header sent when fetching manifests, for registries serving localized annotations. By default no header is sent.",
+				},
+
+				"cache_dir": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Directory of the local OCI cache the pulled blobs and manifests are read through, taking precedence over the `ORAS_CACHE` environment variable. By default nothing is cached unless `ORAS_CACHE` is set.",
+				},
+
+				"lockfile": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path of a JSON lockfile recording the digest each artifact reference resolved to. When a reference is locked, the locked digest is pulled instead of resolving the reference again.",
+				},
+
+				"update_lockfile": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Resolve all references again and refresh the entries of the `lockfile`.",
+				},
+
+				"pin_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path of a file written with the digest each pulled artifact reference resolved to, e.g. to commit as a dependency lock for other tools. It is rewritten with the references pulled by each run and never read by the provider.",
+				},
+
+				"pin_file_format": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      pinFormatJSON,
+					ValidateFunc: validation.StringInSlice([]string{pinFormatJSON, pinFormatYAML, pinFormatTOML}, false),
+					Description:  "The format of the `pin_file`, `json`, `yaml` or `toml`, mapping each reference to its digest. Defaults to `json`.",
+				},
+
+				"audit_log": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path of a file to which a JSON line is appended for every artifact pulled, with the `time`, `reference`, resolved `digest` and `bytes_downloaded`, as an auditable record of the content fetched.",
+				},
+
+				"duplicate_registry_auth": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "error",
+					ValidateFunc: validation.StringInSlice([]string{"error", "warn"}, false),
+					Description:  "How to handle multiple `registry_auth` blocks for the same registry, e.g. addresses only differing by scheme: `error`, or `warn` in which case the block with the first address in sorted order is used, e.g. `ghcr.io` before `https://ghcr.io`. Defaults to `error`.",
+				},
+
+				"tls_renegotiation": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "never",
+					ValidateFunc: validation.StringInSlice([]string{"never", "once", "freely"}, false),
+					Description: "Whether registries may request a TLS renegotiation, required by some enterprise appliances: `never`, `once` per connection, or `freely`. Defaults to `never`. " +
+						"Renegotiation is only possible up to TLS 1.2 and weakens the security of the connection, e.g. the server identity may change during a renegotiation, only enable it for registries requiring it.",
+				},
+
+				"tls_cipher_suites": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Description: "The cipher suites allowed when connecting to registries, by their IANA name, e.g. `TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`. " +
+						"Only applies to TLS 1.2 and lower, the cipher suites of TLS 1.3 are not configurable. By default the Go defaults are used.",
+				},
+
+				"ca_cert_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path to a file with PEM encoded CA certificates trusted when verifying the certificates of registries, in addition to the CA certificates of the system.",
+				},
+
+				"ca_cert_pem": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM encoded CA certificates trusted when verifying the certificates of registries, in addition to the CA certificates of the system and `ca_cert_file`.",
+				},
+
+				"copy_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description: "The number of times the whole copy of an artifact is retried when it fails, with an exponential backoff starting at 1 second. " +
+						"`oras_artifact_file` retries from a clean temporary directory, `oras_artifact` overwrites the files of the failed attempt. Defaults to `0`.",
+				},
+
+				"copy_concurrency": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description: "The maximum number of blobs copied concurrently when pulling or copying an artifact, " +
+						"higher values speed up artifacts with many layers over high-latency links. Defaults to the default of oras-go, currently `3`.",
+				},
+
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      3,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description: "The number of times a registry request is retried after a network error or a `429`, `502`, `503` or `504` response, " +
+						"with an exponential backoff honoring the `Retry-After` header of the registry. " +
+						"Uploads are only retried after a `429`, or a `503` with a `Retry-After` header, as they may not be sent twice. Defaults to `3`.",
+				},
+
+				"retry_http2_stream_errors": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Resume a download reset by an HTTP/2 `INTERNAL_ERROR` or `REFUSED_STREAM` stream error with a range request for the remaining content, up to `max_retries` times, instead of failing the whole copy. Defaults to `true`.",
+				},
+
+				"retry_min_backoff": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "1s",
+					ValidateFunc: validateDuration,
+					Description:  "The time to wait before the first retry of a registry request, doubled for every subsequent retry. Defaults to `1s`.",
+				},
+
+				"retry_max_backoff": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "30s",
+					ValidateFunc: validateDuration,
+					Description:  "The maximum time to wait between two retries of a registry request, also capping the wait requested by a `Retry-After` header. Defaults to `30s`.",
+				},
+
+				"max_connections": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The maximum number of requests in flight to registries at once, across all data sources and resources. By default the number of requests is not limited.",
+				},
+
+				"prefetch": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Fetch the child manifests of an index in the background as soon as the index is read, so resolving nested indexes overlaps with the download of the blobs. Reduces the time to pull large or deeply nested indexes. Defaults to `false`.",
+				},
+
+				"deadline": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDuration,
+					Description:  "Maximum duration, e.g. `15m`, measured from the configuration of the provider, by which all registry calls of the run must be completed. Calls still running at the deadline are cancelled. By default there is no deadline.",
+				},
+
+				"http_proxy": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The proxy used for plain HTTP registries, e.g. `http://proxy.example.com:3128`. When any of the proxy options is set, the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables are ignored.",
+				},
+
+				"https_proxy": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The proxy used for HTTPS registries, e.g. `http://proxy.example.com:3128`.",
+				},
+
+				"no_proxy": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Comma separated hosts, domains and CIDR ranges of the registries accessed without proxy, in the format of the `NO_PROXY` environment variable.",
+				},
+
+				"timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateDuration,
+					Description:  "Maximum duration, e.g. `5m`, of the registry calls of each data source read and resource operation, so a stalled registry can't hang the run. By default there is no timeout.",
+				},
+
+				"auto_plain_http_localhost": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Use plain HTTP instead of HTTPS for registries on `localhost` or a loopback address, e.g. `127.0.0.1:5000` or `[::1]:5000`, as commonly used for local development. Other registries always use HTTPS. Defaults to `false`.",
+				},
+
+				"strip_auth_on_redirect": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+					Description: "Whether to remove the `Authorization` header when a registry redirects to another host, e.g. object storage serving the blobs, so the credentials are not leaked to it. Disable only for registries redirecting to hosts that require the same credentials. Defaults to `true`.",
+				},
+
+				"force_refresh": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Description: "References which are always resolved and fetched again from the registry, bypassing the `lockfile` and the local blob cache, e.g. tags known to move. " +
+						"References match exactly, or by prefix when ending with `*`, e.g. `ghcr.io/org/app:*`.",
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"oras_artifact":      dataSourceOrasArtifact(),
-				"oras_artifact_file": dataSourceOrasArtifactFile(),
+				"oras_artifact":          dataSourceOrasArtifact(),
+				"oras_artifact_file":     dataSourceOrasArtifactFile(),
+				"oras_artifact_files":    dataSourceOrasArtifactFiles(),
+				"oras_artifact_metadata": dataSourceOrasArtifactMetadata(),
+				"oras_blob_exists":       dataSourceOrasBlobExists(),
+				"oras_cache_stats":       dataSourceOrasCacheStats(),
+				"oras_catalog":           dataSourceOrasCatalog(),
+				"oras_channel":           dataSourceOrasChannel(),
+				"oras_compute_digest":    dataSourceOrasComputeDigest(),
+				"oras_digest":            dataSourceOrasDigest(),
+				"oras_digests":           dataSourceOrasDigests(),
+				"oras_layers":            dataSourceOrasLayers(),
+				"oras_layers_by_title":   dataSourceOrasLayersByTitle(),
+				"oras_last_pushed":       dataSourceOrasLastPushed(),
+				"oras_manifest":          dataSourceOrasManifest(),
+				"oras_merged_sbom":       dataSourceOrasMergedSBOM(),
+				"oras_reference_parse":   dataSourceOrasReferenceParse(),
+				"oras_referrers":         dataSourceOrasReferrers(),
+				"oras_registry_tls":      dataSourceOrasRegistryTLS(),
+				"oras_sbom":              dataSourceOrasSBOM(),
+				"oras_semver_tag":        dataSourceOrasSemverTag(),
+				"oras_tags":              dataSourceOrasTags(),
+			},
+			ResourcesMap: map[string]*schema.Resource{
+				"oras_cache_gc": resourceOrasCacheGC(),
+				"oras_copy":     resourceOrasCopy(),
+				"oras_push":     resourceOrasPush(),
+				"oras_tag":      resourceOrasTag(),
 			},
-			ResourcesMap: map[string]*schema.Resource{},
+		}
+
+		for _, r := range p.DataSourcesMap {
+			withDeadline(r)
+			withRegistryWarnings(r)
+		}
+		for _, r := range p.ResourcesMap {
+			withDeadline(r)
+			withRegistryWarnings(r)
 		}
 
 		p.ConfigureContextFunc = configure(version)
@@ -84,78 +574,478 @@ func New(version string) func() *schema.Provider {
 }
 
 type clients struct {
-	version string
-	client  *auth.Client
+	version         string
+	client          *auth.Client
+	maxManifestSize int64
+	cacheDir        string
+	lockfile        *lockfile
+	pinFile         *pinFile
+	auditLog        *auditLog
+	rewrites        []referenceRewrite
+	mirrors         []registryMirror
+	defaultRegistry string
+	copyRetries     int
+	// copyConcurrency is the maximum number of blobs copied concurrently,
+	// zero for the default of oras-go.
+	copyConcurrency int
+	prefetch        bool
+	cacheCounters   cacheCounters
+	cacheGroup      cache.Group
+	// reportedWarnings are the registry warnings already reported.
+	reportedWarnings reportedWarnings
+	// plainHTTPHosts are the registries accessed over plain HTTP.
+	plainHTTPHosts map[string]bool
+	// autoPlainHTTPLocalhost enables plain HTTP for registries on localhost.
+	autoPlainHTTPLocalhost bool
+	// deadline is the time by which all registry calls must be completed,
+	// zero when there is no deadline.
+	deadline time.Time
+	// timeout is the maximum duration of a single operation, zero when there
+	// is no timeout.
+	timeout time.Duration
+	// forceRefresh are the references bypassing the lockfile and the cache.
+	forceRefresh []string
+}
+
+// referenceRewrite is a regular expression replacement applied to references
+// before they are parsed.
+type referenceRewrite struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// expandReference applies the reference rewrites and the default registry to
+// reference. The returned reference is the one of the artifact, even when it
+// is pulled from a mirror.
+func (c *clients) expandReference(reference string) string {
+	for _, rewrite := range c.rewrites {
+		reference = rewrite.pattern.ReplaceAllString(reference, rewrite.replacement)
+	}
+	if c.defaultRegistry != "" && !hasRegistry(reference) {
+		reference = c.defaultRegistry + "/" + reference
+	}
+	return reference
 }
 
 func (c *clients) NewRepository(reference string) (repo *remote.Repository, err error) {
-	repo, err = remote.NewRepository(reference)
+	repo, err = remote.NewRepository(c.expandReference(reference))
 	if err != nil {
 		return nil, err
 	}
 	repo.Client = c.client
+	repo.MaxMetadataBytes = c.maxManifestSize
+	repo.PlainHTTP = c.usePlainHTTP(repo.Reference.Host())
 	return
 }
 
+func (c *clients) NewRegistry(host string) (reg *remote.Registry, err error) {
+	reg, err = remote.NewRegistry(host)
+	if err != nil {
+		return nil, err
+	}
+	reg.Client = c.client
+	reg.PlainHTTP = c.usePlainHTTP(reg.Reference.Host())
+	return
+}
+
+// usePlainHTTP reports whether the registry host is accessed over plain HTTP.
+func (c *clients) usePlainHTTP(host string) bool {
+	return c.plainHTTPHosts[host] || c.autoPlainHTTPLocalhost && isLocalhost(host)
+}
+
+// hasRegistry reports whether reference starts with a registry host, following
+// the rules of docker: the first path component is a registry when it contains
+// a `.` or a `:`, or is `localhost`.
+func hasRegistry(reference string) bool {
+	first, _, found := strings.Cut(reference, "/")
+	if !found {
+		return false
+	}
+	return strings.ContainsAny(first, ".:") || first == "localhost"
+}
+
+// isLocalhost reports whether host, optionally with a port, is localhost or
+// a loopback address.
+func isLocalhost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// CacheRoot returns the directory of the local OCI cache, the cache_dir of
+// the provider or else the ORAS_CACHE environment variable.
+func (c *clients) CacheRoot() string {
+	if c.cacheDir != "" {
+		return c.cacheDir
+	}
+	return os.Getenv("ORAS_CACHE")
+}
+
 func (c *clients) CachedTarget(src oras.ReadOnlyTarget) (oras.ReadOnlyTarget, error) {
-	root := os.Getenv("ORAS_CACHE")
+	root := c.CacheRoot()
 	if root != "" {
 		ociStore, err := oci.New(root)
 		if err != nil {
 			return nil, err
 		}
-		return cache.New(src, ociStore), nil
+		return cache.NewShared(src, ociStore, &c.cacheGroup), nil
 	}
 	return src, nil
 }
 
+// refreshes reports whether reference is configured to bypass the lockfile and
+// the cache.
+func (c *clients) refreshes(reference string) bool {
+	for _, r := range c.forceRefresh {
+		if prefix, ok := strings.CutSuffix(r, "*"); ok {
+			if strings.HasPrefix(reference, prefix) {
+				return true
+			}
+		} else if r == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceTarget returns repo read through the cache, unless reference is forced
+// to be refreshed.
+func (c *clients) SourceTarget(reference string, repo *remote.Repository) (oras.ReadOnlyTarget, error) {
+	if c.refreshes(reference) {
+		return repo, nil
+	}
+	return c.CachedTarget(repo)
+}
+
 func configure(version string) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
+		var diags diag.Diagnostics
 
 		creds := make(map[string]auth.Credential)
+		credFuncs := make(map[string]credentialFunc)
 
 		if v, ok := d.GetOk("registry_auth"); ok {
+			for _, hostname := range duplicateRegistryAddresses(v.(*schema.Set)) {
+				if d.Get("duplicate_registry_auth").(string) == "error" {
+					return nil, diag.Errorf("Error loading registry auth config: multiple registry_auth blocks for registry '%s'", hostname)
+				}
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("Multiple registry_auth blocks for registry '%s'", hostname),
+					Detail:   "Only the block with the first address in sorted order is used for the registry.",
+				})
+			}
+
+			if err := checkCredentialModes(v.(*schema.Set)); err != nil {
+				return nil, diag.Errorf("Error loading registry auth config: %s", err)
+			}
+
 			configureCreds, err := providerSetToCredentials(v.(*schema.Set))
 			if err != nil {
 				return nil, diag.Errorf("Error loading registry auth config: %s", err)
 			}
 			creds = configureCreds
+
+			if credFuncs, err = providerSetToCredentialFuncs(v.(*schema.Set)); err != nil {
+				return nil, diag.Errorf("Error loading registry auth config: %s", err)
+			}
+		}
+
+		registries := make(map[string]registryConfig)
+
+		if v, ok := d.GetOk("registry_auth"); ok {
+			var err error
+			if registries, err = providerSetToRegistryConfigs(v.(*schema.Set)); err != nil {
+				return nil, diag.Errorf("Error loading registry auth config: %s", err)
+			}
+		}
+
+		cipherSuites, err := parseCipherSuites(expandStringList(d.Get("tls_cipher_suites").([]any)))
+		if err != nil {
+			return nil, diag.Errorf("Error configuring TLS: %s", err)
+		}
+
+		rootCAs, err := loadCACertPool(d.Get("ca_cert_file").(string), d.Get("ca_cert_pem").(string))
+		if err != nil {
+			return nil, diag.Errorf("Error configuring TLS: %s", err)
+		}
+
+		minBackoff, _ := time.ParseDuration(d.Get("retry_min_backoff").(string))
+		maxBackoff, _ := time.ParseDuration(d.Get("retry_max_backoff").(string))
+		if maxBackoff < minBackoff {
+			return nil, diag.Errorf("retry_max_backoff (%s) must not be less than retry_min_backoff (%s)", maxBackoff, minBackoff)
+		}
+
+		config := clientConfig{
+			version:        version,
+			network:        d.Get("network").(string),
+			acceptLanguage: d.Get("accept_language").(string),
+			renegotiation:  tlsRenegotiation[d.Get("tls_renegotiation").(string)],
+			cipherSuites:   cipherSuites,
+			rootCAs:        rootCAs,
+			maxConnections: int64(d.Get("max_connections").(int)),
+			maxRetries:     d.Get("max_retries").(int),
+			minBackoff:     minBackoff,
+			maxBackoff:     maxBackoff,
+			resumeStreams:  d.Get("retry_http2_stream_errors").(bool),
+			proxy:          expandProxyConfig(d),
+			creds:          creds,
+			credFuncs:      credFuncs,
+			registries:     registries,
+
+			stripAuthOnRedirect: d.Get("strip_auth_on_redirect").(bool),
 		}
 
-		client, err := authClient(version, creds)
+		client, err := authClient(config)
 		if err != nil {
 			return nil, diag.Errorf("Error creating client: %s", err)
 		}
 
-		return &clients{version: version, client: client}, nil
+		c := &clients{
+			version:         version,
+			client:          client,
+			maxManifestSize: int64(d.Get("max_manifest_size").(int)),
+			copyRetries:     d.Get("copy_retries").(int),
+			copyConcurrency: d.Get("copy_concurrency").(int),
+			prefetch:        d.Get("prefetch").(bool),
+			defaultRegistry: strings.TrimSuffix(d.Get("default_registry").(string), "/"),
+
+			autoPlainHTTPLocalhost: d.Get("auto_plain_http_localhost").(bool),
+			plainHTTPHosts:         make(map[string]bool),
+			forceRefresh:           expandStringList(d.Get("force_refresh").([]any)),
+		}
+		for hostname, registry := range registries {
+			if registry.plainHTTP {
+				c.plainHTTPHosts[hostname] = true
+			}
+		}
+
+		for _, v := range d.Get("reference_rewrite").([]any) {
+			rewrite := v.(map[string]any)
+			pattern, err := regexp.Compile(rewrite["pattern"].(string))
+			if err != nil {
+				return nil, diag.Errorf("Error compiling reference_rewrite pattern: %s", err)
+			}
+			c.rewrites = append(c.rewrites, referenceRewrite{pattern: pattern, replacement: rewrite["replacement"].(string)})
+		}
+
+		for _, v := range d.Get("registry_mirror").([]any) {
+			mirror := v.(map[string]any)
+			c.mirrors = append(c.mirrors, registryMirror{
+				registry:   convertToHostname(mirror["registry"].(string)),
+				endpoint:   convertToHostname(mirror["endpoint"].(string)),
+				pathPrefix: strings.Trim(mirror["path_prefix"].(string), "/"),
+			})
+		}
+
+		if v, ok := d.GetOk("deadline"); ok {
+			duration, _ := time.ParseDuration(v.(string))
+			c.deadline = time.Now().Add(duration)
+		}
+		if v, ok := d.GetOk("timeout"); ok {
+			c.timeout, _ = time.ParseDuration(v.(string))
+		}
+
+		if v, ok := d.GetOk("cache_dir"); ok {
+			if c.cacheDir, err = homedir.Expand(v.(string)); err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+
+		if v, ok := d.GetOk("lockfile"); ok {
+			path, err := homedir.Expand(v.(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			if c.lockfile, err = loadLockfile(path, d.Get("update_lockfile").(bool)); err != nil {
+				return nil, diag.Errorf("Error loading lockfile: %s", err)
+			}
+		}
+
+		if v, ok := d.GetOk("pin_file"); ok {
+			path, err := homedir.Expand(v.(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			c.pinFile = newPinFile(path, d.Get("pin_file_format").(string))
+		}
+
+		if v, ok := d.GetOk("audit_log"); ok {
+			path, err := homedir.Expand(v.(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			c.auditLog = &auditLog{path: path}
+		}
+
+		return c, diags
+	}
+}
+
+// tlsRenegotiation maps the values of the tls_renegotiation option to the
+// renegotiation support of the TLS client.
+var tlsRenegotiation = map[string]tls.RenegotiationSupport{
+	"never":  tls.RenegotiateNever,
+	"once":   tls.RenegotiateOnceAsClient,
+	"freely": tls.RenegotiateFreelyAsClient,
+}
+
+// parseCipherSuites returns the IDs of the cipher suites with the given names,
+// failing on unknown names.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
 	}
+
+	known := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite '%s'", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
-func authClient(version string, creds map[string]auth.Credential) (client *auth.Client, err error) {
+// loadCACertPool returns the CA certificates of the system, extended with the
+// PEM encoded certificates of file and pemData, or nil when neither is set.
+func loadCACertPool(file, pemData string) (*x509.CertPool, error) {
+	if file == "" && pemData == "" {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
 	if err != nil {
-		return nil, err
+		pool = x509.NewCertPool()
+	}
+
+	if file != "" {
+		path, err := homedir.Expand(file)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca_cert_file %s contains no valid PEM encoded certificates", file)
+		}
+	}
+	if pemData != "" && !pool.AppendCertsFromPEM([]byte(pemData)) {
+		return nil, errors.New("ca_cert_pem contains no valid PEM encoded certificates")
+	}
+	return pool, nil
+}
+
+// clientConfig holds the settings used to create the registry client.
+type clientConfig struct {
+	version        string
+	network        string
+	acceptLanguage string
+	renegotiation  tls.RenegotiationSupport
+	cipherSuites   []uint16
+	rootCAs        *x509.CertPool
+	maxConnections int64
+	maxRetries     int
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	resumeStreams  bool
+	proxy          *httpproxy.Config
+	creds          map[string]auth.Credential
+	credFuncs      map[string]credentialFunc
+	registries     map[string]registryConfig
+
+	stripAuthOnRedirect bool
+}
+
+func authClient(config clientConfig) (client *auth.Client, err error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	proxy := http.ProxyFromEnvironment
+	if config.proxy != nil {
+		proxyFunc := config.proxy.ProxyFunc()
+		proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	base := &http.Transport{
+		Proxy: proxy,
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, config.network, addr)
+		},
+		TLSClientConfig: &tls.Config{
+			Renegotiation: config.renegotiation,
+			CipherSuites:  config.cipherSuites,
+			RootCAs:       config.rootCAs,
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	var transport http.RoundTripper = base
+	hosts := make(map[string]http.RoundTripper)
+	for hostname, registry := range config.registries {
+		if !registry.insecureSkipVerify && registry.clientCert == nil {
+			continue
+		}
+		t := base.Clone()
+		t.TLSClientConfig.InsecureSkipVerify = registry.insecureSkipVerify
+		if registry.clientCert != nil {
+			t.TLSClientConfig.Certificates = []tls.Certificate{*registry.clientCert}
+		}
+		hosts[hostname] = t
+	}
+	if len(hosts) > 0 {
+		transport = &hostTransport{base: transport, hosts: hosts}
+	}
+	if config.maxConnections > 0 {
+		transport = &limitTransport{base: transport, sem: semaphore.NewWeighted(config.maxConnections)}
+	}
+	if config.maxRetries > 0 {
+		// outside of the connection limit, so no connection slot is held while waiting
+		transport = &retryTransport{
+			base:          transport,
+			maxRetries:    config.maxRetries,
+			minBackoff:    config.minBackoff,
+			maxBackoff:    config.maxBackoff,
+			resumeStreams: config.resumeStreams,
+		}
 	}
 	client = &auth.Client{
 		Client: &http.Client{
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-				ForceAttemptHTTP2:     true,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       90 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
+			Transport: &registryTransport{
+				registries:     config.registries,
+				acceptLanguage: config.acceptLanguage,
+				base:           &warningTransport{base: transport},
 			},
+			CheckRedirect: checkRedirect(config.stripAuthOnRedirect),
 		},
 		Cache: auth.NewCache(),
 	}
-	client.SetUserAgent("terraform-provider-oras/" + version)
+	client.SetUserAgent("terraform-provider-oras/" + config.version)
 	client.Credential = func(ctx context.Context, s string) (auth.Credential, error) {
 		hostname := convertToHostname(s)
-		if cred, ok := creds[hostname]; ok {
+		if fn, ok := config.credFuncs[hostname]; ok {
+			return fn(ctx)
+		}
+		if cred, ok := config.creds[hostname]; ok {
 			return cred, nil
 		}
 		return auth.EmptyCredential, nil
@@ -163,18 +1053,41 @@ func authClient(version string, creds map[string]auth.Credential) (client *auth.
 	return
 }
 
+// expandProxyConfig returns the proxy configuration of the provider, or nil
+// when it is not set and the proxy is configured by the environment.
+func expandProxyConfig(d *schema.ResourceData) *httpproxy.Config {
+	config := &httpproxy.Config{
+		HTTPProxy:  d.Get("http_proxy").(string),
+		HTTPSProxy: d.Get("https_proxy").(string),
+		NoProxy:    d.Get("no_proxy").(string),
+	}
+	if *config == (httpproxy.Config{}) {
+		return nil
+	}
+	return config
+}
+
 func providerSetToCredentials(authList *schema.Set) (map[string]auth.Credential, error) {
 	credentials := make(map[string]auth.Credential)
 
-	for _, registryAuth := range authList.List() {
+	for _, authMap := range registryAuthBlocks(authList) {
 		cred := auth.Credential{}
-		authMap := registryAuth.(map[string]interface{})
 		hostname := convertToHostname(authMap["address"].(string))
 
-		if username, ok := authMap["username"].(string); ok && username != "" {
-			password := authMap["password"].(string)
+		if authMap["anonymous"].(bool) {
+			credentials[hostname] = auth.EmptyCredential
+			continue
+		}
+		if hasCredentialFunc(authMap) {
+			continue
+		}
+
+		username, _ := authMap["username"].(string)
+		refreshToken, _ := authMap["refresh_token"].(string)
+		if username != "" || refreshToken != "" {
 			cred.Username = username
-			cred.Password = password
+			cred.Password = authMap["password"].(string)
+			cred.RefreshToken = refreshToken
 		} else if configFileContent, ok := authMap["config_file_content"].(string); ok && configFileContent != "" {
 			r := strings.NewReader(configFileContent)
 
@@ -182,12 +1095,16 @@ func providerSetToCredentials(authList *schema.Set) (map[string]auth.Credential,
 			if err != nil {
 				return nil, fmt.Errorf("error parsing docker registry config json: %v", err)
 			}
+			if err := checkCredentialHelper(c, hostname); err != nil {
+				return nil, err
+			}
 			authFileConfig, err := c.GetAuthConfig(hostname)
 			if err != nil {
 				return nil, fmt.Errorf("couldn't find registry config for '%s' in file content", hostname)
 			}
 			cred.Username = authFileConfig.Username
 			cred.Password = authFileConfig.Password
+			cred.RefreshToken = authFileConfig.IdentityToken
 		} else if configFile, ok := authMap["config_file"].(string); ok && configFile != "" {
 			filePath, err := homedir.Expand(configFile)
 			if err != nil {
@@ -202,12 +1119,16 @@ func providerSetToCredentials(authList *schema.Set) (map[string]auth.Credential,
 			if err != nil {
 				return nil, fmt.Errorf("could not read and load config file: %v", err)
 			}
+			if err := checkCredentialHelper(c, hostname); err != nil {
+				return nil, err
+			}
 			authFileConfig, err := c.GetAuthConfig(hostname)
 			if err != nil {
 				return nil, fmt.Errorf("could not get auth config (the credentialhelper did not work or was not found): %v", err)
 			}
 			cred.Username = authFileConfig.Username
 			cred.Password = authFileConfig.Password
+			cred.RefreshToken = authFileConfig.IdentityToken
 		}
 
 		credentials[hostname] = cred
@@ -216,6 +1137,135 @@ func providerSetToCredentials(authList *schema.Set) (map[string]auth.Credential,
 	return credentials, nil
 }
 
+// duplicateRegistryAddresses returns the hostnames configured by more than
+// one registry_auth block.
+func duplicateRegistryAddresses(authList *schema.Set) []string {
+	var duplicates []string
+	seen := make(map[string]int)
+
+	for _, registryAuth := range authList.List() {
+		authMap := registryAuth.(map[string]interface{})
+		hostname := convertToHostname(authMap["address"].(string))
+
+		seen[hostname]++
+		if seen[hostname] == 2 {
+			duplicates = append(duplicates, hostname)
+		}
+	}
+
+	sort.Strings(duplicates)
+	return duplicates
+}
+
+// registryAuthBlocks returns the registry_auth blocks sorted by address,
+// keeping only the first block of every registry. The order of a set is not
+// the order of the configuration, blocks with the same address are ordered by
+// their hash so the block used for a registry is always the same.
+func registryAuthBlocks(authList *schema.Set) []map[string]any {
+	list := authList.List()
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].(map[string]any)["address"].(string), list[j].(map[string]any)["address"].(string)
+		if a != b {
+			return a < b
+		}
+		return authList.F(list[i]) < authList.F(list[j])
+	})
+
+	var blocks []map[string]any
+	seen := make(map[string]bool)
+	for _, registryAuth := range list {
+		authMap := registryAuth.(map[string]any)
+		hostname := convertToHostname(authMap["address"].(string))
+		if seen[hostname] {
+			continue
+		}
+		seen[hostname] = true
+		blocks = append(blocks, authMap)
+	}
+	return blocks
+}
+
+func providerSetToRegistryConfigs(authList *schema.Set) (map[string]registryConfig, error) {
+	registries := make(map[string]registryConfig)
+
+	for _, authMap := range registryAuthBlocks(authList) {
+		hostname := convertToHostname(authMap["address"].(string))
+
+		config := registryConfig{}
+		if userAgent, ok := authMap["user_agent"].(string); ok {
+			config.userAgent = userAgent
+		}
+		if rawAuthorization, ok := authMap["raw_authorization"].(string); ok {
+			config.rawAuthorization = rawAuthorization
+		}
+		if insecureSkipVerify, ok := authMap["insecure_skip_verify"].(bool); ok {
+			config.insecureSkipVerify = insecureSkipVerify
+		}
+		if plainHTTP, ok := authMap["plain_http"].(bool); ok {
+			config.plainHTTP = plainHTTP
+		}
+		// DevSkim: ignore DS137138
+		if strings.HasPrefix(authMap["address"].(string), "http://") {
+			config.plainHTTP = true
+		}
+
+		cert, err := loadClientCertificate(authMap)
+		if err != nil {
+			return nil, fmt.Errorf("registry_auth for '%s': %w", hostname, err)
+		}
+		config.clientCert = cert
+
+		registries[hostname] = config
+	}
+
+	return registries, nil
+}
+
+// loadClientCertificate returns the client certificate of a registry_auth
+// block, read from files or given as PEM, or nil when it has none.
+func loadClientCertificate(authMap map[string]any) (*tls.Certificate, error) {
+	certPEM, _ := authMap["client_cert_pem"].(string)
+	keyPEM, _ := authMap["client_key_pem"].(string)
+	if certFile, _ := authMap["client_cert_file"].(string); certFile != "" {
+		if certPEM != "" {
+			return nil, errors.New("only one of client_cert_file and client_cert_pem can be set")
+		}
+		data, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client_cert_file: %w", err)
+		}
+		certPEM = string(data)
+	}
+	if keyFile, _ := authMap["client_key_file"].(string); keyFile != "" {
+		if keyPEM != "" {
+			return nil, errors.New("only one of client_key_file and client_key_pem can be set")
+		}
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client_key_file: %w", err)
+		}
+		keyPEM = string(data)
+	}
+
+	switch {
+	case certPEM == "" && keyPEM == "":
+		return nil, nil
+	case certPEM == "":
+		return nil, errors.New("a client key requires a client certificate")
+	case keyPEM == "":
+		return nil, errors.New("a client certificate requires a client key")
+	}
+
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+// loadConfigFile parses a docker config file. Credentials are looked up with
+// GetAuthConfig, which follows the precedence of docker: a registry specific
+// credHelpers entry first, then the credsStore, then the auths of the file.
 func loadConfigFile(configData io.Reader) (*configfile.ConfigFile, error) {
 	configFile := configfile.New("")
 	if err := configFile.LoadFromReader(configData); err != nil {
@@ -226,6 +1276,46 @@ func loadConfigFile(configData io.Reader) (*configfile.ConfigFile, error) {
 	return configFile, nil
 }
 
+// checkCredentialHelper returns an error when the docker config delegates the
+// credentials of hostname to a credential helper which is not installed, as
+// the docker config would silently yield empty credentials otherwise.
+func checkCredentialHelper(c *configfile.ConfigFile, hostname string) error {
+	helper := c.CredentialsStore
+	if h, ok := c.CredentialHelpers[hostname]; ok {
+		helper = h
+	}
+	if helper == "" {
+		return nil
+	}
+	if _, err := exec.LookPath("docker-credential-" + helper); err != nil {
+		return fmt.Errorf("credential helper docker-credential-%s configured for '%s' was not found in PATH", helper, hostname)
+	}
+	return nil
+}
+
+func validateDigest(v any, k string) (warnings []string, errs []error) {
+	if _, err := digest.Parse(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a valid digest: %v", k, err))
+	}
+	return
+}
+
+func expandStringMap(m map[string]any) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+func expandStringList(l []any) []string {
+	result := make([]string, 0, len(l))
+	for _, v := range l {
+		result = append(result, v.(string))
+	}
+	return result
+}
+
 func convertToHostname(url string) string {
 	stripped := url
 	// DevSkim: ignore DS137138
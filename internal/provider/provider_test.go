@@ -0,0 +1,415 @@
+package provider
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func registryAuthSet(t *testing.T, blocks ...map[string]any) *schema.Set {
+	t.Helper()
+	resource := New("test")().Schema["registry_auth"].Elem.(*schema.Resource)
+	data := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"registry_auth": {Type: schema.TypeSet, Optional: true, Elem: resource},
+	}, map[string]any{
+		"registry_auth": toAnySlice(blocks),
+	})
+	return data.Get("registry_auth").(*schema.Set)
+}
+
+func toAnySlice(blocks []map[string]any) []any {
+	result := make([]any, len(blocks))
+	for i, b := range blocks {
+		result[i] = b
+	}
+	return result
+}
+
+// installCredentialHelper installs a docker credential helper in PATH,
+// returning the given username and secret for any registry.
+func installCredentialHelper(t *testing.T, name, username, secret string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nread server\necho \"{\\\"ServerURL\\\":\\\"$server\\\",\\\"Username\\\":\\\"" + username + "\\\",\\\"Secret\\\":\\\"" + secret + "\\\"}\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-"+name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProviderSetToCredentials_precedence(t *testing.T) {
+	installCredentialHelper(t, "test", "helper-user", "helper-pass")
+
+	fileAuth := base64.StdEncoding.EncodeToString([]byte("file-user:file-pass"))
+	tokenAuth := base64.StdEncoding.EncodeToString([]byte("00000000-0000-0000-0000-000000000000:"))
+
+	tests := []struct {
+		name   string
+		config string
+		want   auth.Credential
+	}{
+		{
+			name:   "auths only",
+			config: `{"auths": {"registry.example.com": {"auth": "` + fileAuth + `"}}}`,
+			want:   auth.Credential{Username: "file-user", Password: "file-pass"},
+		},
+		{
+			name:   "credsStore overrides auths",
+			config: `{"auths": {"registry.example.com": {"auth": "` + fileAuth + `"}}, "credsStore": "test"}`,
+			want:   auth.Credential{Username: "helper-user", Password: "helper-pass"},
+		},
+		{
+			name:   "credHelpers overrides auths",
+			config: `{"auths": {"registry.example.com": {"auth": "` + fileAuth + `"}}, "credHelpers": {"registry.example.com": "test"}}`,
+			want:   auth.Credential{Username: "helper-user", Password: "helper-pass"},
+		},
+		{
+			name:   "credHelpers for another registry",
+			config: `{"auths": {"registry.example.com": {"auth": "` + fileAuth + `"}}, "credHelpers": {"other.example.com": "test"}}`,
+			want:   auth.Credential{Username: "file-user", Password: "file-pass"},
+		},
+		{
+			name:   "identity token",
+			config: `{"auths": {"registry.example.com": {"auth": "` + tokenAuth + `", "identitytoken": "id-token"}}}`,
+			want:   auth.Credential{Username: "00000000-0000-0000-0000-000000000000", RefreshToken: "id-token"},
+		},
+		{
+			name:   "credHelpers overrides credsStore",
+			config: `{"credsStore": "missing", "credHelpers": {"registry.example.com": "test"}}`,
+			want:   auth.Credential{Username: "helper-user", Password: "helper-pass"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(configFile, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			for source, block := range map[string]map[string]any{
+				"config_file_content": {"address": "registry.example.com", "config_file_content": tt.config},
+				"config_file":         {"address": "registry.example.com", "config_file": configFile},
+			} {
+				creds, err := providerSetToCredentials(registryAuthSet(t, block))
+				if err != nil {
+					t.Fatalf("providerSetToCredentials() with %s error = %v", source, err)
+				}
+				if got := creds["registry.example.com"]; got != tt.want {
+					t.Errorf("providerSetToCredentials() with %s = %v, want %v", source, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateRegistryAddresses(t *testing.T) {
+	authList := registryAuthSet(t,
+		map[string]any{"address": "http://registry.example.com", "username": "first"},
+		map[string]any{"address": "https://registry.example.com", "username": "second"},
+		map[string]any{"address": "other.example.com", "username": "other"},
+	)
+
+	got := duplicateRegistryAddresses(authList)
+	want := []string{"registry.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("duplicateRegistryAddresses() = %v, want %v", got, want)
+	}
+
+	authList = registryAuthSet(t,
+		map[string]any{"address": "registry.example.com", "username": "first"},
+		map[string]any{"address": "other.example.com", "username": "other"},
+	)
+	if got := duplicateRegistryAddresses(authList); len(got) != 0 {
+		t.Errorf("duplicateRegistryAddresses() = %v, want none", got)
+	}
+}
+
+func TestProviderSetToCredentials_duplicates(t *testing.T) {
+	// the block with the first address in sorted order is used, whatever
+	// the order of the blocks
+	for i := 0; i < 10; i++ {
+		authList := registryAuthSet(t,
+			map[string]any{"address": "https://registry.example.com", "username": "second", "password": fmt.Sprint(i)},
+			map[string]any{"address": "registry.example.com", "username": "first", "password": fmt.Sprint(i)},
+			map[string]any{"address": "http://registry.example.com", "username": "third", "password": fmt.Sprint(i)},
+		)
+		creds, err := providerSetToCredentials(authList)
+		if err != nil {
+			t.Fatal("providerSetToCredentials() error =", err)
+		}
+		if got := creds["registry.example.com"].Username; got != "third" {
+			t.Errorf("providerSetToCredentials() username = %s, want the block of http://registry.example.com", got)
+		}
+	}
+}
+
+func TestConvertToHostname(t *testing.T) {
+	tests := map[string]string{
+		"registry.example.com":                  "registry.example.com",
+		"https://registry.example.com":          "registry.example.com",
+		"http://localhost:5000":                 "localhost:5000",
+		"registry.example.com/org/repo":         "registry.example.com",
+		"https://registry.example.com:8443/org": "registry.example.com:8443",
+	}
+	for url, want := range tests {
+		if got := convertToHostname(url); got != want {
+			t.Errorf("convertToHostname(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	got, err := parseCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_AES_128_CBC_SHA"})
+	if err != nil {
+		t.Fatal("parseCipherSuites() error =", err)
+	}
+	want := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_128_CBC_SHA}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCipherSuites() = %v, want %v", got, want)
+	}
+
+	if _, err := parseCipherSuites([]string{"TLS_UNKNOWN"}); err == nil {
+		t.Error("parseCipherSuites() error = nil, want error for unknown cipher suite")
+	}
+}
+
+func TestNewRepository_rewrites(t *testing.T) {
+	c := &clients{rewrites: []referenceRewrite{
+		{pattern: regexp.MustCompile(`^legacy\.example\.com/`), replacement: "registry.example.com/"},
+		{pattern: regexp.MustCompile(`::(\w+)$`), replacement: ":$1"},
+	}}
+
+	repo, err := c.NewRepository("legacy.example.com/org/app::v1")
+	if err != nil {
+		t.Fatal("NewRepository() error =", err)
+	}
+	if got, want := repo.Reference.String(), "registry.example.com/org/app:v1"; got != want {
+		t.Errorf("NewRepository().Reference = %s, want %s", got, want)
+	}
+}
+
+func TestIsLocalhost(t *testing.T) {
+	tests := map[string]bool{
+		"localhost":             true,
+		"localhost:5000":        true,
+		"LOCALHOST:5000":        true,
+		"127.0.0.1":             true,
+		"127.0.0.1:5000":        true,
+		"[::1]:5000":            true,
+		"::1":                   true,
+		"registry.local:5000":   false,
+		"localhost.example.com": false,
+		"10.0.0.1:5000":         false,
+		"ghcr.io":               false,
+	}
+	for host, want := range tests {
+		if got := isLocalhost(host); got != want {
+			t.Errorf("isLocalhost(%s) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestNewRepository_autoPlainHTTPLocalhost(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		c := &clients{autoPlainHTTPLocalhost: enabled}
+		for reference, local := range map[string]bool{"localhost:5000/app:v1": true, "ghcr.io/org/app:v1": false} {
+			repo, err := c.NewRepository(reference)
+			if err != nil {
+				t.Fatal("NewRepository() error =", err)
+			}
+			if want := enabled && local; repo.PlainHTTP != want {
+				t.Errorf("NewRepository(%s).PlainHTTP = %v with auto_plain_http_localhost %v, want %v", reference, repo.PlainHTTP, enabled, want)
+			}
+		}
+	}
+}
+
+func TestLoadCACertPool(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, args := range map[string][2]string{"file": {caFile, ""}, "pem": {"", caPEM}} {
+		pool, err := loadCACertPool(args[0], args[1])
+		if err != nil {
+			t.Fatalf("loadCACertPool(%s) error = %v", name, err)
+		}
+		client, err := authClient(clientConfig{network: "tcp", rootCAs: pool})
+		if err != nil {
+			t.Fatal("authClient() error =", err)
+		}
+		resp, err := client.Client.Get(srv.URL)
+		if err != nil {
+			t.Errorf("Get() with CA from %s error = %v", name, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	if pool, err := loadCACertPool("", ""); pool != nil || err != nil {
+		t.Errorf("loadCACertPool() = %v, %v, want nil pool", pool, err)
+	}
+	if _, err := loadCACertPool("", "not a certificate"); err == nil {
+		t.Error("expected error for invalid PEM")
+	}
+}
+
+func TestNewRepository_defaultRegistry(t *testing.T) {
+	dgst := digest.FromString("manifest")
+	c := &clients{
+		defaultRegistry: "registry.example.com",
+		rewrites:        []referenceRewrite{{pattern: regexp.MustCompile(`^legacy/`), replacement: "legacy.example.com/"}},
+	}
+
+	tests := map[string]string{
+		"myrepo:tag":              "registry.example.com/myrepo:tag",
+		"team/app:1.0":            "registry.example.com/team/app:1.0",
+		"ghcr.io/org/app:1.0":     "ghcr.io/org/app:1.0",
+		"localhost/app:1.0":       "localhost/app:1.0",
+		"localhost:5000/app:1.0":  "localhost:5000/app:1.0",
+		"legacy/app:1.0":          "legacy.example.com/app:1.0",
+		"myrepo@" + dgst.String(): "registry.example.com/myrepo@" + dgst.String(),
+	}
+	for reference, want := range tests {
+		repo, err := c.NewRepository(reference)
+		if err != nil {
+			t.Errorf("NewRepository(%s) error = %v", reference, err)
+			continue
+		}
+		if got := repo.Reference.String(); got != want {
+			t.Errorf("NewRepository(%s).Reference = %s, want %s", reference, got, want)
+		}
+	}
+
+	if _, err := (&clients{}).NewRepository("myrepo:tag"); err == nil {
+		t.Error("expected error for a reference without registry when no default registry is set")
+	}
+}
+
+func TestProviderSetToRegistryConfigs_plainHTTP(t *testing.T) {
+	registries, err := providerSetToRegistryConfigs(registryAuthSet(t,
+		map[string]any{"address": "localhost:5000", "plain_http": true},
+		map[string]any{"address": "http://registry.local:5000"},
+		map[string]any{"address": "https://ghcr.io"},
+	))
+	if err != nil {
+		t.Fatal("providerSetToRegistryConfigs() error =", err)
+	}
+
+	for hostname, want := range map[string]bool{"localhost:5000": true, "registry.local:5000": true, "ghcr.io": false} {
+		if got := registries[hostname].plainHTTP; got != want {
+			t.Errorf("plainHTTP of %s = %v, want %v", hostname, got, want)
+		}
+	}
+
+	c := &clients{plainHTTPHosts: map[string]bool{"localhost:5000": true}}
+	for reference, want := range map[string]bool{"localhost:5000/app:v1": true, "localhost:5001/app:v1": false} {
+		repo, err := c.NewRepository(reference)
+		if err != nil {
+			t.Fatal("NewRepository() error =", err)
+		}
+		if repo.PlainHTTP != want {
+			t.Errorf("NewRepository(%s).PlainHTTP = %v, want %v", reference, repo.PlainHTTP, want)
+		}
+	}
+}
+
+func TestProviderSetToCredentials_anonymous(t *testing.T) {
+	set := registryAuthSet(t, map[string]any{
+		"address":             "registry.example.com",
+		"anonymous":           true,
+		"config_file_content": `{"auths": {"registry.example.com": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("stale:token")) + `"}}}`,
+		"exec":                []any{map[string]any{"command": "does-not-exist-oras"}},
+	})
+
+	creds, err := providerSetToCredentials(set)
+	if err != nil {
+		t.Fatal("providerSetToCredentials() error =", err)
+	}
+	if got, ok := creds["registry.example.com"]; !ok || got != auth.EmptyCredential {
+		t.Errorf("providerSetToCredentials() = %v, want the empty credential", got)
+	}
+
+	funcs, err := providerSetToCredentialFuncs(set)
+	if err != nil {
+		t.Fatal("providerSetToCredentialFuncs() error =", err)
+	}
+	if _, ok := funcs["registry.example.com"]; ok {
+		t.Error("providerSetToCredentialFuncs() returned a credential func for an anonymous registry")
+	}
+}
+
+func TestProviderSetToCredentials_missingCredentialHelper(t *testing.T) {
+	for _, config := range []string{
+		`{"credsStore": "missing-oras"}`,
+		`{"credHelpers": {"registry.example.com": "missing-oras"}}`,
+	} {
+		_, err := providerSetToCredentials(registryAuthSet(t, map[string]any{
+			"address":             "registry.example.com",
+			"config_file_content": config,
+		}))
+		if err == nil || !strings.Contains(err.Error(), "docker-credential-missing-oras") {
+			t.Errorf("providerSetToCredentials() with %s error = %v, want a missing helper error", config, err)
+		}
+	}
+}
+
+func TestClientsRefreshes(t *testing.T) {
+	c := &clients{forceRefresh: []string{"ghcr.io/org/app:latest", "ghcr.io/org/tools:*"}}
+
+	tests := map[string]bool{
+		"ghcr.io/org/app:latest": true,
+		"ghcr.io/org/app:v1":     false,
+		"ghcr.io/org/tools:v1":   true,
+		"ghcr.io/org/tools":      false,
+		"ghcr.io/org/toolsx:v1":  false,
+	}
+	for reference, want := range tests {
+		if got := c.refreshes(reference); got != want {
+			t.Errorf("refreshes(%s) = %v, want %v", reference, got, want)
+		}
+	}
+}
+
+func TestProviderSetToCredentials_refreshToken(t *testing.T) {
+	creds, err := providerSetToCredentials(registryAuthSet(t, map[string]any{
+		"address":       "registry.example.com",
+		"refresh_token": "id-token",
+	}))
+	if err != nil {
+		t.Fatal("providerSetToCredentials() error =", err)
+	}
+	if got, want := creds["registry.example.com"], (auth.Credential{RefreshToken: "id-token"}); got != want {
+		t.Errorf("providerSetToCredentials() = %v, want %v", got, want)
+	}
+}
+
+func TestClientsCacheRoot(t *testing.T) {
+	t.Setenv("ORAS_CACHE", "/env/cache")
+
+	if got := (&clients{}).CacheRoot(); got != "/env/cache" {
+		t.Errorf("CacheRoot() = %s, want the ORAS_CACHE environment variable", got)
+	}
+	if got := (&clients{cacheDir: "/provider/cache"}).CacheRoot(); got != "/provider/cache" {
+		t.Errorf("CacheRoot() = %s, want the cache_dir of the provider", got)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package provider
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// ramdiskDir returns a tmpfs-backed directory suitable for temporary files,
+// or an empty string when none is available.
+func ramdiskDir() string {
+	for _, dir := range []string{"/dev/shm", os.Getenv("XDG_RUNTIME_DIR")} {
+		if dir == "" {
+			continue
+		}
+		var stat unix.Statfs_t
+		if err := unix.Statfs(dir, &stat); err != nil {
+			continue
+		}
+		if stat.Type == unix.TMPFS_MAGIC && unix.Access(dir, unix.W_OK) == nil {
+			return dir
+		}
+	}
+	return ""
+}
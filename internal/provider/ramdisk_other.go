@@ -0,0 +1,9 @@
+//go:build !linux
+
+package provider
+
+// ramdiskDir returns a tmpfs-backed directory suitable for temporary files,
+// which is only supported on Linux.
+func ramdiskDir() string {
+	return ""
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// findReferrer returns the most recently created referrer of subject with the
+// given artifact type, based on the created annotation of the referrers.
+func findReferrer(ctx context.Context, repo *remote.Repository, subject ocispec.Descriptor, artifactType string) (ocispec.Descriptor, error) {
+	var (
+		found   *ocispec.Descriptor
+		created time.Time
+	)
+	err := repo.Referrers(ctx, subject, artifactType, func(referrers []ocispec.Descriptor) error {
+		for _, referrer := range referrers {
+			if referrer.ArtifactType != artifactType {
+				continue
+			}
+			// referrers without a valid created annotation sort first
+			t, _ := time.Parse(time.RFC3339, referrer.Annotations[ocispec.AnnotationCreated])
+			if found == nil || t.After(created) {
+				referrer := referrer
+				found, created = &referrer, t
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if found == nil {
+		return ocispec.Descriptor{}, fmt.Errorf("referrer of %s with artifact type %s: %w", subject.Digest, artifactType, errdef.ErrNotFound)
+	}
+	return *found, nil
+}
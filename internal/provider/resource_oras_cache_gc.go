@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jsiebens/terraform-provider-oras/internal/cache"
+)
+
+func resourceOrasCacheGC() *schema.Resource {
+	return &schema.Resource{
+		Description: "Prunes unreferenced blobs from the local OCI cache (`cache_dir` or `ORAS_CACHE`). " +
+			"The garbage collection runs when the resource is created, or replaced because one of its arguments changed.",
+
+		CreateContext: resourceOrasCacheGCCreate,
+		ReadContext:   schema.NoopContext,
+		DeleteContext: resourceOrasCacheGCDelete,
+
+		Schema: map[string]*schema.Schema{
+			"keep_manifests": {
+				Description:  "The number of most recently cached manifests to keep, together with the content they refer to. Defaults to `10`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"triggers": {
+				Description: "Arbitrary map of values that, when changed, will run the garbage collection again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"blobs_removed": {
+				Description: "The number of blobs removed from the cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"bytes_reclaimed": {
+				Description: "The total size in bytes of the blobs removed from the cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceOrasCacheGCCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	root := opts.CacheRoot()
+	if root == "" {
+		return diag.Errorf("no cache directory configured, set the cache_dir provider option or the ORAS_CACHE environment variable")
+	}
+
+	result, err := cache.GarbageCollect(ctx, root, d.Get("keep_manifests").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("blobs_removed", result.BlobsRemoved)
+	_ = d.Set("bytes_reclaimed", result.BytesReclaimed)
+
+	d.SetId(strconv.FormatInt(time.Now().UnixNano(), 10))
+
+	return nil
+}
+
+func resourceOrasCacheGCDelete(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
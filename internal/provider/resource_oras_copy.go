@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+func resourceOrasCopy() *schema.Resource {
+	return &schema.Resource{
+		Description: "Copies an artifact from one repository to another, e.g. to mirror it into an internal registry. " +
+			"The artifact is copied again when the source or the destination no longer points to the copied manifest. " +
+			"Destroying the resource leaves the copy in the destination registry.",
+
+		CreateContext: resourceOrasCopyCreate,
+		ReadContext:   resourceOrasCopyRead,
+		DeleteContext: resourceOrasCopyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"source_reference": {
+				Description: "The reference of the artifact to copy, e.g. `ghcr.io/org/app:1.0`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"destination_reference": {
+				Description: "The reference to copy the artifact to, e.g. `registry.internal/mirror/app:1.0`. " +
+					"Without tag the artifact is only copied by digest.",
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"recursive": {
+				Description: "Whether to also copy the referrers of the artifact, such as signatures and SBOMs. Defaults to `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
+			"digest": {
+				Description: "The digest of the copied manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"bytes_downloaded": {
+				Description: "The number of bytes fetched from the source registry, excluding content served from the local cache.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// graphTarget adds the predecessors of a repository to a target wrapping it,
+// such as the cached target of the repository.
+type graphTarget struct {
+	oras.ReadOnlyTarget
+	finder content.PredecessorFinder
+}
+
+func (t *graphTarget) Predecessors(ctx context.Context, node ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	return t.finder.Predecessors(ctx, node)
+}
+
+func resourceOrasCopyCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	srcReference := d.Get("source_reference").(string)
+	srcRepo, err := opts.newReadRepository(srcReference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dstRepo, err := opts.NewRepository(d.Get("destination_reference").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the source is read through the cache, so repeated copies are fast
+	src, err := opts.SourceTarget(srcReference, srcRepo)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	srcRef := srcRepo.Reference.Reference
+	root, err := srcRepo.Resolve(ctx, srcRef)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, srcRepo, err))
+	}
+	// copy by digest, so the tags of source and destination can't diverge
+	srcRef = root.Digest.String()
+
+	dstRef := dstRepo.Reference.Reference
+	if dstRef == "" {
+		dstRef = srcRef
+	}
+
+	var stats copyStats
+	copyOpts := opts.copyOptions(src, &stats)
+
+	var desc ocispec.Descriptor
+	if d.Get("recursive").(bool) {
+		extendedOpts := oras.ExtendedCopyOptions{ExtendedCopyGraphOptions: oras.ExtendedCopyGraphOptions{CopyGraphOptions: copyOpts.CopyGraphOptions}}
+		desc, err = oras.ExtendedCopy(ctx, &graphTarget{ReadOnlyTarget: src, finder: srcRepo}, srcRef, dstRepo, dstRef, extendedOpts)
+	} else {
+		desc, err = oras.Copy(ctx, src, srcRef, dstRepo, dstRef, copyOpts)
+	}
+	if err != nil {
+		return diag.Errorf("failed to copy %s to %s: %v", d.Get("source_reference"), d.Get("destination_reference"), err)
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+	_ = d.Set("bytes_downloaded", stats.bytesDownloaded)
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
+
+func resourceOrasCopyRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	for _, key := range []string{"source_reference", "destination_reference"} {
+		reference := d.Get(key).(string)
+		repo, err := opts.NewRepository(reference)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		ref := repo.Reference.Reference
+		if ref == "" {
+			ref = d.Id()
+		}
+		desc, err := repo.Resolve(ctx, ref)
+		if errors.Is(err, errdef.ErrNotFound) {
+			log.Printf("[WARN] Artifact %s not found, removing copy from state", reference)
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to resolve %s: %w", reference, err))
+		}
+		if desc.Digest.String() != d.Id() {
+			log.Printf("[WARN] Artifact %s points to %s instead of the copied %s, removing copy from state", reference, desc.Digest, d.Id())
+			d.SetId("")
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func resourceOrasCopyDelete(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
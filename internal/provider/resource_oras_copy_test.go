@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestResourceOrasCopyRead(t *testing.T) {
+	copied := digest.FromString("copied")
+	other := digest.FromString("other")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var dgst digest.Digest
+		switch r.URL.Path {
+		case "/v2/src/manifests/v1", "/v2/dst/manifests/v1", "/v2/dst/manifests/" + copied.String():
+			dgst = copied
+		case "/v2/src/manifests/v2":
+			dgst = other
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+		w.Header().Set("Content-Length", "100")
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+
+	tests := []struct {
+		name        string
+		source      string
+		destination string
+		wantExists  bool
+	}{
+		{name: "unchanged", source: "src:v1", destination: "dst:v1", wantExists: true},
+		{name: "by digest", source: "src:v1", destination: "dst", wantExists: true},
+		{name: "source moved", source: "src:v2", destination: "dst:v1"},
+		{name: "destination deleted", source: "src:v1", destination: "dst:v3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceOrasCopy().Schema, map[string]any{
+				"source_reference":      u.Host + "/" + tt.source,
+				"destination_reference": u.Host + "/" + tt.destination,
+			})
+			d.SetId(copied.String())
+
+			if diags := resourceOrasCopyRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("resourceOrasCopyRead() = %v", diags)
+			}
+			if exists := d.Id() != ""; exists != tt.wantExists {
+				t.Errorf("resource exists = %v, want %v", exists, tt.wantExists)
+			}
+		})
+	}
+}
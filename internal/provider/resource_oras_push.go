@@ -0,0 +1,305 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+func resourceOrasPush() *schema.Resource {
+	return &schema.Resource{
+		Description: "Pushes local files as an OCI artifact. " +
+			"The artifact is pushed again when one of the arguments or the content of one of the files changes. " +
+			"The manifest only has a created annotation when set in `annotations`, so pushing the same files results in the same digest, " +
+			"as computed by the `oras_compute_digest` data source.",
+
+		CreateContext: resourceOrasPushCreate,
+		ReadContext:   resourceOrasPushRead,
+		DeleteContext: resourceOrasPushDelete,
+		CustomizeDiff: resourceOrasPushCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference to push the artifact to, including a tag, e.g. `ghcr.io/me/thing:1.0`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"files": {
+				Description: "The files to push, each as a layer titled with the name of the file.",
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Description: "The path of the file.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"media_type": {
+							Description: "The media type of the layer.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     ocispec.MediaTypeImageLayer,
+						},
+					},
+				},
+			},
+			"artifact_type": {
+				Description: "The artifact type of the manifest. Defaults to `application/vnd.unknown.artifact.v1` without `config`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"config": packConfigSchema(true),
+			"annotations": {
+				Description: "The annotations of the manifest.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"file_digests": {
+				Description: "The digests of the pushed files, by path.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"digest": {
+				Description: "The digest of the pushed manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// pushFile is a file pushed as layer by oras_push.
+type pushFile struct {
+	path      string
+	mediaType string
+}
+
+func expandPushFiles(v []any) []pushFile {
+	files := make([]pushFile, 0, len(v))
+	for _, f := range v {
+		m := f.(map[string]any)
+		files = append(files, pushFile{path: m["path"].(string), mediaType: m["media_type"].(string)})
+	}
+	return files
+}
+
+// fileDigests returns the digests of the content of files, by path.
+func fileDigests(files []pushFile) (map[string]any, error) {
+	digests := make(map[string]any, len(files))
+	for _, f := range files {
+		fp, err := os.Open(f.path)
+		if err != nil {
+			return nil, err
+		}
+		dgst, err := digest.FromReader(fp)
+		fp.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+		}
+		digests[f.path] = dgst.String()
+	}
+	return digests, nil
+}
+
+// resourceOrasPushCustomizeDiff replaces the artifact when the content of one
+// of the files changed since it was pushed.
+func resourceOrasPushCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	digests, err := fileDigests(expandPushFiles(d.Get("files").([]any)))
+	if err != nil {
+		// the file may only be created during the apply
+		return d.SetNewComputed("file_digests")
+	}
+
+	old := d.Get("file_digests").(map[string]any)
+	changed := len(old) != len(digests)
+	for path, dgst := range digests {
+		if old[path] != dgst {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := d.SetNew("file_digests", digests); err != nil {
+		return err
+	}
+	return d.ForceNew("file_digests")
+}
+
+func resourceOrasPushCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	repo, err := opts.NewRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tag := repo.Reference.Reference
+	if err := repo.Reference.ValidateReferenceAsTag(); err != nil {
+		return diag.Errorf("reference %s must include a tag", reference)
+	}
+
+	files := expandPushFiles(d.Get("files").([]any))
+	digests, err := fileDigests(files)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	store, err := file.New("")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer store.Close()
+
+	layers, err := addPushFiles(ctx, store, files)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	config, err := expandPackConfig(d.Get("config").([]any))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := packArtifact(ctx, store, d.Get("artifact_type").(string), config, layers, expandStringMap(d.Get("annotations").(map[string]any)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := store.Tag(ctx, desc, tag); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := oras.Copy(ctx, store, tag, repo, tag, oras.DefaultCopyOptions); err != nil {
+		return diag.Errorf("failed to push %s: %v", reference, err)
+	}
+
+	_ = d.Set("file_digests", digests)
+	_ = d.Set("digest", desc.Digest.String())
+
+	d.SetId(desc.Digest.String())
+
+	return nil
+}
+
+// addPushFiles adds files to store, returning their layer descriptors. The
+// descriptors only depend on the name, media type and content of the files,
+// so pushing unchanged files results in the same layers.
+func addPushFiles(ctx context.Context, store *file.Store, files []pushFile) ([]ocispec.Descriptor, error) {
+	var layers []ocispec.Descriptor
+	for _, f := range files {
+		fi, err := os.Stat(f.path)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			return nil, fmt.Errorf("%s is a directory, only files can be pushed", f.path)
+		}
+
+		desc, err := store.Add(ctx, filepath.Base(f.path), f.mediaType, f.path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, desc)
+	}
+	return layers, nil
+}
+
+func resourceOrasPushRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	repo, err := opts.NewRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if errors.Is(err, errdef.ErrNotFound) {
+		log.Printf("[WARN] Artifact %s not found, removing from state", reference)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if desc.Digest.String() != d.Id() {
+		log.Printf("[WARN] Tag of %s points to %s instead, removing from state", reference, desc.Digest)
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+
+	return nil
+}
+
+func resourceOrasPushDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	repo, err := opts.NewRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, d.Id())
+	if errors.Is(err, errdef.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = repo.Delete(ctx, desc)
+	var errResp *errcode.ErrorResponse
+	switch {
+	case errors.Is(err, errdef.ErrNotFound):
+	case errors.As(err, &errResp) && (errResp.StatusCode == http.StatusMethodNotAllowed || errResp.StatusCode == http.StatusBadRequest && hasErrorCode(errResp, errcode.ErrorCodeUnsupported)):
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Registry does not support deleting %s", reference),
+			Detail:   "The artifact is removed from the state, but is left in the registry.",
+		}}
+	case err != nil:
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func hasErrorCode(errResp *errcode.ErrorResponse, code string) bool {
+	for _, e := range errResp.Errors {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/file"
+)
+
+func TestAddPushFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("key: value\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := []pushFile{{path: path, mediaType: "application/vnd.test.config"}}
+
+	add := func() []ocispec.Descriptor {
+		store, err := file.New("")
+		if err != nil {
+			t.Fatal("file.New() error =", err)
+		}
+		defer store.Close()
+
+		layers, err := addPushFiles(context.Background(), store, files)
+		if err != nil {
+			t.Fatal("addPushFiles() error =", err)
+		}
+		return layers
+	}
+
+	first, second := add(), add()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("addPushFiles() is not stable: %v != %v", first, second)
+	}
+	want := ocispec.Descriptor{
+		MediaType:   "application/vnd.test.config",
+		Digest:      digest.FromString("key: value\n"),
+		Size:        11,
+		Annotations: map[string]string{ocispec.AnnotationTitle: "config.yaml"},
+	}
+	if len(first) != 1 || !reflect.DeepEqual(first[0], want) {
+		t.Errorf("addPushFiles() = %v, want [%v]", first, want)
+	}
+
+	digests, err := fileDigests(files)
+	if err != nil {
+		t.Fatal("fileDigests() error =", err)
+	}
+	if digests[path] != want.Digest.String() {
+		t.Errorf("fileDigests() = %v, want %s", digests, want.Digest)
+	}
+}
+
+func TestAddPushFiles_directory(t *testing.T) {
+	store, err := file.New("")
+	if err != nil {
+		t.Fatal("file.New() error =", err)
+	}
+	defer store.Close()
+
+	if _, err := addPushFiles(context.Background(), store, []pushFile{{path: t.TempDir(), mediaType: ocispec.MediaTypeImageLayer}}); err == nil {
+		t.Error("expected error pushing a directory")
+	}
+}
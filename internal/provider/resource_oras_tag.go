@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+func resourceOrasTag() *schema.Resource {
+	return &schema.Resource{
+		Description: "Tags an existing manifest. The tag is created again when it was moved to another manifest outside of Terraform, " +
+			"and removed on destroy when the registry supports deleting tags.",
+
+		CreateContext: resourceOrasTagCreate,
+		ReadContext:   resourceOrasTagRead,
+		DeleteContext: resourceOrasTagDelete,
+
+		Schema: map[string]*schema.Schema{
+			"reference": {
+				Description: "The reference of the manifest to tag, typically by digest, e.g. `ghcr.io/me/thing@sha256:...`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"tag": {
+				Description: "The tag to create in the repository of `reference`.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: func(v any, k string) (warnings []string, errs []error) {
+					if err := (registry.Reference{Reference: v.(string)}).ValidateReferenceAsTag(); err != nil {
+						errs = append(errs, fmt.Errorf("%q is not a valid tag: %v", k, err))
+					}
+					return
+				},
+			},
+			"digest": {
+				Description: "The digest of the tagged manifest.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceOrasTagCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	reference := d.Get("reference").(string)
+	tag := d.Get("tag").(string)
+
+	repo, err := opts.NewRepository(reference)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return diag.FromErr(explainResolveError(ctx, repo, err))
+	}
+
+	if err := repo.Tag(ctx, desc, tag); err != nil {
+		if isImmutableTagError(err) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Registry rejected tagging %s as %s", reference, tag),
+				Detail: fmt.Sprintf("The repository appears to have immutable tags, so an existing tag cannot be moved to another manifest: %v. "+
+					"Use a new tag, or allow mutable tags in the settings of the repository.", err),
+			}}
+		}
+		return diag.FromErr(err)
+	}
+
+	_ = d.Set("digest", desc.Digest.String())
+
+	d.SetId(fmt.Sprintf("%s/%s:%s", repo.Reference.Registry, repo.Reference.Repository, tag))
+
+	return nil
+}
+
+func resourceOrasTagRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repo, err := opts.NewRepository(d.Get("reference").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tag := d.Get("tag").(string)
+	desc, err := repo.Resolve(ctx, tag)
+	if errors.Is(err, errdef.ErrNotFound) {
+		log.Printf("[WARN] Tag %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if desc.Digest.String() != d.Get("digest").(string) {
+		log.Printf("[WARN] Tag %s points to %s instead, removing from state", d.Id(), desc.Digest)
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceOrasTagDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	opts := meta.(*clients)
+
+	repo, err := opts.NewRepository(d.Get("reference").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	deleted, err := untag(ctx, repo, d.Get("tag").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !deleted {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Registry does not support deleting tag %s", d.Id()),
+			Detail:   "The tag is removed from the state, but is left in the registry.",
+		}}
+	}
+
+	return nil
+}
+
+// untag deletes tag from repo, without deleting the manifest it points to. It
+// returns false when the registry does not support deleting tags.
+func untag(ctx context.Context, repo *remote.Repository, tag string) (bool, error) {
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, repo.Reference.Host(), repo.Reference.Repository, tag)
+
+	ctx = auth.AppendScopes(ctx, auth.ScopeRepository(repo.Reference.Repository, auth.ActionDelete))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := repo.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK, http.StatusNotFound:
+		return true, nil
+	case http.StatusMethodNotAllowed, http.StatusBadRequest:
+		return false, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("failed to delete tag %s: unexpected status %s: %s", tag, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// isImmutableTagError reports whether err is returned by a registry refusing
+// to move a tag because the repository has immutable tags.
+func isImmutableTagError(err error) bool {
+	var errResp *errcode.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return false
+	}
+	if errResp.StatusCode == http.StatusConflict {
+		return true
+	}
+	for _, e := range errResp.Errors {
+		if strings.Contains(strings.ToLower(e.Message), "immutable") || e.Code == "TAG_INVALID" {
+			return true
+		}
+	}
+	return false
+}
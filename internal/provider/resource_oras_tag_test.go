@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+func TestUntag(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1":
+			w.WriteHeader(http.StatusAccepted)
+		case "/v2/app/manifests/unsupported":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case "/v2/app/manifests/denied":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal("url.Parse() error =", err)
+	}
+	c := &clients{client: &auth.Client{Client: srv.Client()}}
+	repo, err := c.NewRepository(u.Host + "/app")
+	if err != nil {
+		t.Fatal("NewRepository() error =", err)
+	}
+
+	tests := []struct {
+		tag     string
+		want    bool
+		wantErr bool
+	}{
+		{tag: "v1", want: true},
+		{tag: "missing", want: true},
+		{tag: "unsupported", want: false},
+		{tag: "denied", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := untag(context.Background(), repo, tt.tag)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("untag(%s) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("untag(%s) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestIsImmutableTagError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"conflict", &errcode.ErrorResponse{StatusCode: http.StatusConflict}, true},
+		{"tag invalid", &errcode.ErrorResponse{StatusCode: http.StatusBadRequest, Errors: errcode.Errors{{Code: "TAG_INVALID"}}}, true},
+		{"immutable message", &errcode.ErrorResponse{StatusCode: http.StatusForbidden, Errors: errcode.Errors{{Code: "DENIED", Message: "tag is immutable"}}}, true},
+		{"denied", &errcode.ErrorResponse{StatusCode: http.StatusForbidden, Errors: errcode.Errors{{Code: "DENIED"}}}, false},
+		{"other", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := isImmutableTagError(tt.err); got != tt.want {
+			t.Errorf("isImmutableTagError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
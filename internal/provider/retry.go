@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryTransport retries the requests failing with a network error or a
+// status indicating a transient failure of the registry, with an exponential
+// backoff between the attempts. Requests which are not idempotent, e.g. blob
+// uploads, are only retried when the registry did not process them.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// resumeStreams resumes the download of a response body reset by an
+	// HTTP/2 stream error, instead of failing the whole copy.
+	resumeStreams bool
+}
+
+// isRetryableStatus reports whether a response with the status code is worth
+// retrying.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotent reports whether a request with the method can be sent again
+// after a failure, whether or not the registry processed it.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// notProcessed reports whether a response proves the registry did not process
+// the request, so that even a blob upload can be sent again: a 429, or a 503
+// with a Retry-After header.
+func notProcessed(resp *http.Response, err error) bool {
+	if err != nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != ""
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+
+		if attempt == t.maxRetries || req.Context().Err() != nil {
+			return t.resumable(req, resp), err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return t.resumable(req, resp), nil
+		}
+		if !isIdempotent(req.Method) && !notProcessed(resp, err) {
+			return resp, err
+		}
+		// the body of a request can only be sent again when it can be rewound
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if err == nil {
+			// a registry may ask for hours, which would stall the whole run
+			if after, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = after
+				if wait > t.maxBackoff {
+					wait = t.maxBackoff
+				}
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// resumable wraps the body of a successful response to a GET request, so it
+// is resumed when the download is reset by an HTTP/2 stream error.
+func (t *retryTransport) resumable(req *http.Request, resp *http.Response) *http.Response {
+	if !t.resumeStreams || resp == nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp
+	}
+	resp.Body = &resumableBody{transport: t, req: req, body: resp.Body}
+	return resp
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isHTTP2StreamError reports whether err is an HTTP/2 stream reset with an
+// INTERNAL_ERROR or REFUSED_STREAM code, after which the request can be
+// retried on another stream. The stream errors of net/http are unexported,
+// hence they are recognized by their message.
+func isHTTP2StreamError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "stream error:") &&
+		(strings.Contains(msg, "INTERNAL_ERROR") || strings.Contains(msg, "REFUSED_STREAM"))
+}
+
+// resumableBody is the body of a response which, when reading it fails with
+// an HTTP/2 stream error, requests the remaining content again with a range
+// request. The content is still verified against its digest by the caller.
+type resumableBody struct {
+	transport *retryTransport
+	req       *http.Request
+	body      io.ReadCloser
+	read      int64
+	retries   int
+}
+
+func (b *resumableBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.read += int64(n)
+	if !isHTTP2StreamError(err) || b.retries >= b.transport.maxRetries {
+		return n, err
+	}
+
+	log.Printf("[WARN] Resuming download of %s at byte %d after %v", b.req.URL, b.read, err)
+	if err := b.resume(); err != nil {
+		return n, err
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return b.Read(p)
+}
+
+// resume replaces the body by the content after the bytes already read.
+func (b *resumableBody) resume() error {
+	ctx := b.req.Context()
+	if err := sleepContext(ctx, b.transport.backoff(b.retries)); err != nil {
+		return err
+	}
+	b.retries++
+	b.body.Close()
+
+	req := b.req.Clone(ctx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", b.read))
+	resp, err := b.transport.base.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", b.read)):
+		b.body = resp.Body
+	case resp.StatusCode == http.StatusOK:
+		// the registry ignored the range, skip the content already read
+		if _, err := io.CopyN(io.Discard, resp.Body, b.read); err != nil {
+			resp.Body.Close()
+			return err
+		}
+		b.body = resp.Body
+	default:
+		resp.Body.Close()
+		return fmt.Errorf("failed to resume download of %s: unexpected status %s", b.req.URL, resp.Status)
+	}
+	return nil
+}
+
+func (b *resumableBody) Close() error {
+	return b.body.Close()
+}
+
+// backoff returns the time to wait before retrying after attempt, doubling
+// with every attempt up to the maximum backoff.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	wait := t.minBackoff
+	for i := 0; i < attempt && wait < t.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > t.maxBackoff {
+		return t.maxBackoff
+	}
+	return wait
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	var (
+		requests int
+		bodies   []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch requests {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		base:       http.DefaultTransport,
+		maxRetries: 3,
+		minBackoff: time.Millisecond,
+		maxBackoff: 10 * time.Millisecond,
+	}}
+
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("blob"))
+	if err != nil {
+		t.Fatal("Post() error =", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+	for i, body := range bodies {
+		if body != "blob" {
+			t.Errorf("body of request %d = %q, want the body sent again", i+1, body)
+		}
+	}
+}
+
+func TestRetryTransport_notIdempotent(t *testing.T) {
+	tests := []struct {
+		method       string
+		status       int
+		retryAfter   string
+		wantRequests int
+	}{
+		{method: http.MethodPut, status: http.StatusBadGateway, wantRequests: 1},
+		{method: http.MethodPost, status: http.StatusServiceUnavailable, wantRequests: 1},
+		{method: http.MethodPatch, status: http.StatusServiceUnavailable, retryAfter: "0", wantRequests: 2},
+		{method: http.MethodPut, status: http.StatusTooManyRequests, wantRequests: 2},
+		{method: http.MethodGet, status: http.StatusBadGateway, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		client := &http.Client{Transport: &retryTransport{
+			base:       http.DefaultTransport,
+			maxRetries: 3,
+			minBackoff: time.Millisecond,
+			maxBackoff: 10 * time.Millisecond,
+		}}
+		req, err := http.NewRequest(tt.method, srv.URL, strings.NewReader("blob"))
+		if err != nil {
+			t.Fatal("http.NewRequest() error =", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s with %d: Do() error = %v", tt.method, tt.status, err)
+		}
+		resp.Body.Close()
+		srv.Close()
+
+		if requests != tt.wantRequests {
+			t.Errorf("%s with %d: requests = %d, want %d", tt.method, tt.status, requests, tt.wantRequests)
+		}
+	}
+}
+
+func TestRetryTransport_exhausted(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 2, minBackoff: time.Millisecond, maxBackoff: time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal("Get() error =", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway || requests != 3 {
+		t.Errorf("status = %d after %d requests, want %d after 3", resp.StatusCode, requests, http.StatusBadGateway)
+	}
+}
+
+func TestRetryTransport_retryAfterCapped(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 1, minBackoff: time.Millisecond, maxBackoff: 10 * time.Millisecond},
+		Timeout:   5 * time.Second,
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal("Get() error =", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || requests != 2 {
+		t.Errorf("status = %d after %d requests, want %d after 2", resp.StatusCode, requests, http.StatusOK)
+	}
+}
+
+func TestRetryTransport_cancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 3, minBackoff: time.Minute, maxBackoff: time.Minute}}
+	start := time.Now()
+	if _, err := client.Do(req); err == nil {
+		t.Error("Do() error = nil, want the context error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Do() returned after %s, want it to stop waiting when the context is done", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"120": 2 * time.Minute,
+		"0":   0,
+		time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat): 0,
+	}
+	for v, want := range tests {
+		got, ok := parseRetryAfter(v)
+		if !ok || got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s", v, got, ok, want)
+		}
+	}
+	for _, v := range []string{"", "-1", "soon"} {
+		if _, ok := parseRetryAfter(v); ok {
+			t.Errorf("parseRetryAfter(%q) ok = true, want false", v)
+		}
+	}
+}
+
+func TestRetryTransport_http2StreamError(t *testing.T) {
+	blob := bytes.Repeat([]byte("0123456789"), 10000)
+	var requests atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// reset the stream halfway, which HTTP/2 reports as INTERNAL_ERROR
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			_, _ = w.Write(blob[:len(blob)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "blob", time.Time{}, bytes.NewReader(blob))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		base:          srv.Client().Transport,
+		maxRetries:    2,
+		minBackoff:    time.Millisecond,
+		maxBackoff:    time.Millisecond,
+		resumeStreams: true,
+	}}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal("Get() error =", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("response protocol = %s, want HTTP/2", resp.Proto)
+	}
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal("ReadAll() error =", err)
+	}
+	if !bytes.Equal(got, blob) {
+		t.Errorf("body has %d bytes, want the %d bytes of the blob", len(got), len(blob))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d, want 2", n)
+	}
+}
+
+func TestIsHTTP2StreamError(t *testing.T) {
+	tests := map[string]bool{
+		"stream error: stream ID 3; INTERNAL_ERROR; received from peer": true,
+		"stream error: stream ID 5; REFUSED_STREAM":                     true,
+		"stream error: stream ID 5; PROTOCOL_ERROR":                     false,
+		"unexpected EOF": false,
+	}
+	for msg, want := range tests {
+		if got := isHTTP2StreamError(errors.New(msg)); got != want {
+			t.Errorf("isHTTP2StreamError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Media types of the supported and known SBOM formats.
+const (
+	mediaTypeCycloneDXJSON = "application/vnd.cyclonedx+json"
+	mediaTypeCycloneDXXML  = "application/vnd.cyclonedx+xml"
+	mediaTypeSPDXJSON      = "application/spdx+json"
+	mediaTypeSPDX          = "text/spdx"
+)
+
+var sbomMediaTypes = []string{mediaTypeCycloneDXJSON, mediaTypeCycloneDXXML, mediaTypeSPDXJSON, mediaTypeSPDX}
+
+// sbomComponent is a component listed in an SBOM.
+type sbomComponent struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	PURL    string `json:"purl"`
+	Type    string `json:"type"`
+}
+
+func (c sbomComponent) key() string {
+	if c.PURL != "" {
+		return c.PURL
+	}
+	return c.Name + "@" + c.Version
+}
+
+func (c sbomComponent) toMap() map[string]any {
+	return map[string]any{
+		"name":    c.Name,
+		"version": c.Version,
+		"purl":    c.PURL,
+		"type":    c.Type,
+	}
+}
+
+// sbomComponentSchema is the schema of the components read from an SBOM.
+func sbomComponentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the component.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"version": {
+			Description: "The version of the component.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"purl": {
+			Description: "The package URL of the component.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"type": {
+			Description: "The type of the component, e.g. `library`.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+}
+
+// sbomReferrers returns the referrers of subject which are SBOMs.
+func sbomReferrers(ctx context.Context, repo *remote.Repository, subject ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	var sboms []ocispec.Descriptor
+	for _, artifactType := range sbomMediaTypes {
+		err := repo.Referrers(ctx, subject, artifactType, func(referrers []ocispec.Descriptor) error {
+			sboms = append(sboms, referrers...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sboms, nil
+}
+
+// fetchSBOMDocuments fetches the SBOM documents stored in the layers or blobs
+// of the manifest described by desc.
+func fetchSBOMDocuments(ctx context.Context, fetcher content.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, [][]byte, error) {
+	data, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest struct {
+		ArtifactType string               `json:"artifactType"`
+		Layers       []ocispec.Descriptor `json:"layers"`
+		Blobs        []ocispec.Descriptor `json:"blobs"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		blobs     = append(manifest.Layers, manifest.Blobs...)
+		documents [][]byte
+	)
+	for i, blob := range blobs {
+		// SBOM tools use the artifact type or the layer media type for the format
+		if !isSBOMMediaType(blob.MediaType) && isSBOMMediaType(manifest.ArtifactType) {
+			blobs[i].MediaType = manifest.ArtifactType
+		}
+		document, err := content.FetchAll(ctx, fetcher, blob)
+		if err != nil {
+			return nil, nil, err
+		}
+		documents = append(documents, document)
+	}
+	return blobs, documents, nil
+}
+
+func isSBOMMediaType(mediaType string) bool {
+	for _, m := range sbomMediaTypes {
+		if m == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSBOM parses the components of an SBOM document of the given media type.
+func parseSBOM(mediaType string, document []byte) ([]sbomComponent, error) {
+	switch mediaType {
+	case mediaTypeCycloneDXJSON:
+		return parseCycloneDXJSON(document)
+	case mediaTypeCycloneDXXML:
+		return parseCycloneDXXML(document)
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format '%s', only CycloneDX JSON (%s) and XML (%s) are supported", mediaType, mediaTypeCycloneDXJSON, mediaTypeCycloneDXXML)
+	}
+}
+
+type cycloneDXComponent struct {
+	sbomComponent
+	Components []cycloneDXComponent `json:"components"`
+}
+
+func parseCycloneDXJSON(document []byte) ([]sbomComponent, error) {
+	var bom struct {
+		BOMFormat  string               `json:"bomFormat"`
+		Components []cycloneDXComponent `json:"components"`
+	}
+	if err := json.Unmarshal(document, &bom); err != nil {
+		return nil, fmt.Errorf("failed to parse CycloneDX SBOM: %w", err)
+	}
+	if bom.BOMFormat != "CycloneDX" {
+		return nil, fmt.Errorf("failed to parse CycloneDX SBOM: unexpected bomFormat '%s'", bom.BOMFormat)
+	}
+
+	var components []sbomComponent
+	var flatten func([]cycloneDXComponent)
+	flatten = func(cs []cycloneDXComponent) {
+		for _, c := range cs {
+			components = append(components, c.sbomComponent)
+			flatten(c.Components)
+		}
+	}
+	flatten(bom.Components)
+	return components, nil
+}
+
+type cycloneDXXMLComponent struct {
+	Type       string                  `xml:"type,attr"`
+	Name       string                  `xml:"name"`
+	Version    string                  `xml:"version"`
+	PURL       string                  `xml:"purl"`
+	Components []cycloneDXXMLComponent `xml:"components>component"`
+}
+
+func parseCycloneDXXML(document []byte) ([]sbomComponent, error) {
+	var bom struct {
+		XMLName    xml.Name
+		Components []cycloneDXXMLComponent `xml:"components>component"`
+	}
+	if err := xml.Unmarshal(document, &bom); err != nil {
+		return nil, fmt.Errorf("failed to parse CycloneDX SBOM: %w", err)
+	}
+	if bom.XMLName.Local != "bom" || !strings.HasPrefix(bom.XMLName.Space, "http://cyclonedx.org/schema/bom/") {
+		return nil, fmt.Errorf("failed to parse CycloneDX SBOM: unexpected root element '%s'", bom.XMLName.Local)
+	}
+
+	var components []sbomComponent
+	var flatten func([]cycloneDXXMLComponent)
+	flatten = func(cs []cycloneDXXMLComponent) {
+		for _, c := range cs {
+			components = append(components, sbomComponent{Name: c.Name, Version: c.Version, PURL: c.PURL, Type: c.Type})
+			flatten(c.Components)
+		}
+	}
+	flatten(bom.Components)
+	return components, nil
+}
+
+// mergeSBOMComponents merges the components of several SBOMs, removing the
+// duplicates, sorted by name and version.
+func mergeSBOMComponents(sboms ...[]sbomComponent) []sbomComponent {
+	seen := make(map[string]bool)
+	var merged []sbomComponent
+	for _, components := range sboms {
+		for _, c := range components {
+			if seen[c.key()] {
+				continue
+			}
+			seen[c.key()] = true
+			merged = append(merged, c)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Name != merged[j].Name {
+			return merged[i].Name < merged[j].Name
+		}
+		if merged[i].Version != merged[j].Version {
+			return merged[i].Version < merged[j].Version
+		}
+		return merged[i].PURL < merged[j].PURL
+	})
+	return merged
+}
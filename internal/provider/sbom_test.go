@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeSBOMComponents(t *testing.T) {
+	first, err := parseCycloneDXJSON([]byte(`{
+		"bomFormat": "CycloneDX",
+		"specVersion": "1.4",
+		"components": [
+			{"type": "library", "name": "zlib", "version": "1.2.13", "purl": "pkg:generic/zlib@1.2.13"},
+			{"type": "library", "name": "openssl", "version": "3.0.8", "purl": "pkg:generic/openssl@3.0.8",
+			 "components": [{"type": "library", "name": "libcrypto", "version": "3.0.8"}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal("parseCycloneDXJSON() error =", err)
+	}
+	second, err := parseCycloneDXJSON([]byte(`{
+		"bomFormat": "CycloneDX",
+		"components": [
+			{"type": "library", "name": "zlib", "version": "1.2.13", "purl": "pkg:generic/zlib@1.2.13"},
+			{"type": "library", "name": "busybox", "version": "1.36.0"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal("parseCycloneDXJSON() error =", err)
+	}
+
+	got := mergeSBOMComponents(first, second)
+	want := []sbomComponent{
+		{Type: "library", Name: "busybox", Version: "1.36.0"},
+		{Type: "library", Name: "libcrypto", Version: "3.0.8"},
+		{Type: "library", Name: "openssl", Version: "3.0.8", PURL: "pkg:generic/openssl@3.0.8"},
+		{Type: "library", Name: "zlib", Version: "1.2.13", PURL: "pkg:generic/zlib@1.2.13"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeSBOMComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSBOM_unsupported(t *testing.T) {
+	if _, err := parseSBOM(mediaTypeSPDXJSON, []byte(`{}`)); err == nil {
+		t.Error("parseSBOM() error = nil, want unsupported format error")
+	}
+	if _, err := parseCycloneDXJSON([]byte(`{"spdxVersion": "SPDX-2.3"}`)); err == nil {
+		t.Error("parseCycloneDXJSON() error = nil, want error for a non CycloneDX document")
+	}
+}
+
+func TestParseCycloneDXXML(t *testing.T) {
+	got, err := parseSBOM(mediaTypeCycloneDXXML, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <components>
+    <component type="library">
+      <name>openssl</name>
+      <version>3.0.8</version>
+      <purl>pkg:generic/openssl@3.0.8</purl>
+      <components>
+        <component type="library">
+          <name>libcrypto</name>
+          <version>3.0.8</version>
+        </component>
+      </components>
+    </component>
+  </components>
+</bom>`))
+	if err != nil {
+		t.Fatal("parseSBOM() error =", err)
+	}
+	want := []sbomComponent{
+		{Type: "library", Name: "openssl", Version: "3.0.8", PURL: "pkg:generic/openssl@3.0.8"},
+		{Type: "library", Name: "libcrypto", Version: "3.0.8"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSBOM() = %v, want %v", got, want)
+	}
+
+	if _, err := parseCycloneDXXML([]byte(`<spdx></spdx>`)); err == nil {
+		t.Error("parseCycloneDXXML() error = nil, want error for a non CycloneDX document")
+	}
+}
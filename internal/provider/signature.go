@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Artifact types of the signatures attached to an artifact.
+const (
+	artifactTypeNotarySignature = "application/vnd.cncf.notary.signature"
+	artifactTypeCosignSignature = "application/vnd.dev.cosign.artifact.sig.v1+json"
+)
+
+// hasSignature reports whether a signature is attached to desc, either as a
+// Notary or Cosign referrer, or using the Cosign tag scheme.
+func hasSignature(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) (bool, error) {
+	for _, artifactType := range []string{artifactTypeNotarySignature, artifactTypeCosignSignature} {
+		found := false
+		err := repo.Referrers(ctx, desc, artifactType, func(referrers []ocispec.Descriptor) error {
+			found = found || len(referrers) > 0
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+		if found {
+			return true, nil
+		}
+	}
+
+	_, err := repo.Resolve(ctx, cosignSignatureTag(desc))
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// cosignSignatureTag returns the tag under which Cosign stores the signatures
+// of desc, e.g. sha256-<hex>.sig
+func cosignSignatureTag(desc ocispec.Descriptor) string {
+	return fmt.Sprintf("%s-%s.sig", desc.Digest.Algorithm(), desc.Digest.Encoded())
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+const (
+	// annotationCosignCertificate is the annotation of the layers of a Cosign
+	// signature holding the PEM encoded signing certificate.
+	annotationCosignCertificate = "dev.sigstore.cosign/certificate"
+
+	// mediaTypeJWS is the media type of the envelope of a Notary signature in
+	// the JWS format.
+	mediaTypeJWS = "application/jose+json"
+)
+
+// OIDs of the Fulcio certificate extensions holding the OIDC issuer, as raw
+// string for the deprecated one and as DER encoded UTF8String for the other.
+var (
+	oidFulcioIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// signerIdentity is the expected identity of the certificate of a signature,
+// Fulcio style. An empty issuer matches any issuer.
+type signerIdentity struct {
+	subject string
+	issuer  string
+}
+
+// matches reports whether the certificate was issued to the identity, its
+// subject being one of the email addresses or URIs of the certificate.
+func (s signerIdentity) matches(cert *x509.Certificate) bool {
+	if s.issuer != "" && certificateIssuer(cert) != s.issuer {
+		return false
+	}
+	for _, email := range cert.EmailAddresses {
+		if email == s.subject {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == s.subject {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateIssuer returns the OIDC issuer recorded in a Fulcio certificate,
+// or an empty string for other certificates.
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidFulcioIssuer):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// signedBy reports whether the signature referrer has a certificate issued to
+// identity. Referrers which are not signatures, or signatures without a
+// certificate, never match.
+func signedBy(ctx context.Context, fetcher content.Fetcher, referrer ocispec.Descriptor, identity signerIdentity) (bool, error) {
+	if referrer.ArtifactType != artifactTypeCosignSignature && referrer.ArtifactType != artifactTypeNotarySignature {
+		return false, nil
+	}
+
+	manifest, err := fetchManifest(ctx, fetcher, referrer)
+	if err != nil {
+		return false, err
+	}
+
+	for _, layer := range manifest.Layers {
+		cert, err := signatureCertificate(ctx, fetcher, layer)
+		if err != nil {
+			return false, fmt.Errorf("signature %s: %w", referrer.Digest, err)
+		}
+		if cert != nil && identity.matches(cert) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// signatureCertificate returns the signing certificate of a signature layer,
+// either from the annotation of a Cosign signature or the certificate chain of
+// a Notary JWS envelope, or nil when it has none.
+func signatureCertificate(ctx context.Context, fetcher content.Fetcher, layer ocispec.Descriptor) (*x509.Certificate, error) {
+	if v, ok := layer.Annotations[annotationCosignCertificate]; ok {
+		block, _ := pem.Decode([]byte(v))
+		if block == nil {
+			return nil, fmt.Errorf("invalid certificate in layer %s", layer.Digest)
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+
+	if layer.MediaType != mediaTypeJWS {
+		return nil, nil
+	}
+	data, err := content.FetchAll(ctx, fetcher, layer)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Header struct {
+			X5C []string `json:"x5c"`
+		} `json:"header"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid JWS envelope in layer %s: %w", layer.Digest, err)
+	}
+	if len(envelope.Header.X5C) == 0 {
+		return nil, nil
+	}
+	// the signing certificate comes first in the chain
+	der, err := base64.StdEncoding.DecodeString(envelope.Header.X5C[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in layer %s: %w", layer.Digest, err)
+	}
+	return x509.ParseCertificate(der)
+}
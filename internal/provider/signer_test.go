@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// generateFulcioCertificate returns a DER encoded certificate issued to
+// subject by the OIDC issuer, like the certificates issued by Fulcio.
+func generateFulcioCertificate(t *testing.T, subject, issuer string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := asn1.MarshalWithParams(issuer, "utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		URIs:            []*url.URL{u},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: value}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestSignedBy(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	const (
+		workflow = "https://github.com/org/repo/.github/workflows/release.yml@refs/heads/main"
+		issuer   = "https://token.actions.githubusercontent.com"
+	)
+	cert := generateFulcioCertificate(t, workflow, issuer)
+	config := pushBlob(t, store, ocispec.MediaTypeScratch, []byte("{}"))
+
+	cosignLayer := pushBlob(t, store, "application/vnd.dev.cosign.simplesigning.v1+json", []byte("{}"))
+	cosignLayer.Annotations = map[string]string{
+		annotationCosignCertificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})),
+	}
+	cosign := pushManifest(t, store, config, cosignLayer)
+	cosign.ArtifactType = artifactTypeCosignSignature
+
+	notary := pushManifest(t, store, config, pushJSON(t, store, mediaTypeJWS, map[string]any{
+		"payload":   "",
+		"protected": "",
+		"header":    map[string]any{"x5c": []string{base64.StdEncoding.EncodeToString(cert)}},
+	}))
+	notary.ArtifactType = artifactTypeNotarySignature
+
+	keyed := pushManifest(t, store, config, pushBlob(t, store, "application/vnd.dev.cosign.simplesigning.v1+json", []byte(`{"keyed":true}`)))
+	keyed.ArtifactType = artifactTypeCosignSignature
+
+	sbom := pushManifest(t, store, config)
+	sbom.ArtifactType = "application/spdx+json"
+
+	tests := []struct {
+		name     string
+		referrer ocispec.Descriptor
+		identity signerIdentity
+		want     bool
+	}{
+		{"cosign", cosign, signerIdentity{subject: workflow, issuer: issuer}, true},
+		{"cosign any issuer", cosign, signerIdentity{subject: workflow}, true},
+		{"cosign other subject", cosign, signerIdentity{subject: "someone@example.com"}, false},
+		{"cosign other issuer", cosign, signerIdentity{subject: workflow, issuer: "https://accounts.google.com"}, false},
+		{"notary", notary, signerIdentity{subject: workflow, issuer: issuer}, true},
+		{"without certificate", keyed, signerIdentity{subject: workflow}, false},
+		{"not a signature", sbom, signerIdentity{subject: workflow}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signedBy(ctx, store, tt.referrer, tt.identity)
+			if err != nil {
+				t.Fatal("signedBy() error =", err)
+			}
+			if got != tt.want {
+				t.Errorf("signedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// registryConfig holds the per-registry settings of a registry_auth block
+// that affect the requests sent to that registry.
+type registryConfig struct {
+	userAgent          string
+	rawAuthorization   string
+	insecureSkipVerify bool
+	plainHTTP          bool
+	clientCert         *tls.Certificate
+}
+
+// registryTransport applies the provider and per-registry settings to each
+// outgoing request, based on the host of the request.
+type registryTransport struct {
+	base           http.RoundTripper
+	registries     map[string]registryConfig
+	acceptLanguage string
+}
+
+func (t *registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	config, ok := t.registries[req.URL.Host]
+	isManifest := strings.Contains(req.URL.Path, "/manifests/")
+	if !ok && (t.acceptLanguage == "" || !isManifest) {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if t.acceptLanguage != "" && isManifest {
+		req.Header.Set("Accept-Language", t.acceptLanguage)
+	}
+	if config.userAgent != "" {
+		req.Header.Set("User-Agent", config.userAgent)
+	}
+	if config.rawAuthorization != "" {
+		req.Header.Set("Authorization", config.rawAuthorization)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// hostTransport sends the requests to the hosts with a dedicated transport,
+// e.g. with other TLS settings, through that transport, and all other requests
+// through base.
+type hostTransport struct {
+	base  http.RoundTripper
+	hosts map[string]http.RoundTripper
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.hosts[req.URL.Host]; ok {
+		return transport.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// checkRedirect returns the redirect policy of the registry client. Unlike the
+// default policy, which keeps the Authorization header on redirects to
+// subdomains, the header is either removed or kept on every redirect to
+// another host, depending on stripAuth.
+func checkRedirect(stripAuth bool) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		prev := via[len(via)-1]
+		if req.URL.Host == via[0].URL.Host {
+			return nil
+		}
+		if stripAuth {
+			req.Header.Del("Authorization")
+		} else if authorization := prev.Header.Get("Authorization"); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return nil
+	}
+}
+
+// limitTransport limits the number of requests in flight through base. A
+// request is in flight until the body of its response is closed.
+type limitTransport struct {
+	base http.RoundTripper
+	sem  *semaphore.Weighted
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.sem.Acquire(req.Context(), 1); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.sem.Release(1)
+		return nil, err
+	}
+
+	var once sync.Once
+	resp.Body = &releaseBody{
+		ReadCloser: resp.Body,
+		release:    func() { once.Do(func() { t.sem.Release(1) }) },
+	}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestLimitTransport(t *testing.T) {
+	var inFlight, maxInFlight int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+		for {
+			max := atomic.LoadInt64(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &limitTransport{base: http.DefaultTransport, sem: semaphore.NewWeighted(2)}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Error("Get() error =", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&maxInFlight); got > 2 {
+		t.Errorf("requests in flight = %d, want at most 2", got)
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer storage.Close()
+
+	// redirect to another host than the one of the registry
+	storageURL := strings.Replace(storage.URL, "127.0.0.1", "localhost", 1)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, storageURL+"/blob", http.StatusTemporaryRedirect)
+	}))
+	defer registry.Close()
+
+	for stripAuth, want := range map[bool]string{true: "", false: "Bearer secret"} {
+		client := &http.Client{CheckRedirect: checkRedirect(stripAuth)}
+		req, err := http.NewRequest(http.MethodGet, registry.URL+"/v2/app/blobs/sha256:abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal("Do() error =", err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(got) != want {
+			t.Errorf("checkRedirect(%v): storage received Authorization %q, want %q", stripAuth, got, want)
+		}
+	}
+}
+
+func TestAuthClient_insecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	for insecure, wantErr := range map[bool]bool{true: false, false: true} {
+		client, err := authClient(clientConfig{
+			network: "tcp",
+			registries: map[string]registryConfig{
+				host:                   {insecureSkipVerify: insecure},
+				"registry.example.com": {insecureSkipVerify: true},
+			},
+		})
+		if err != nil {
+			t.Fatal("authClient() error =", err)
+		}
+
+		resp, err := client.Client.Get(srv.URL)
+		if (err != nil) != wantErr {
+			t.Errorf("Get() with insecure_skip_verify %v error = %v, wantErr %v", insecure, err, wantErr)
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func TestAuthClient_clientCertificate(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	certPEM, keyPEM := generateClientCertificate(t)
+	cert, err := loadClientCertificate(map[string]any{"client_cert_pem": certPEM, "client_key_pem": keyPEM})
+	if err != nil {
+		t.Fatal("loadClientCertificate() error =", err)
+	}
+
+	for name, registries := range map[string]map[string]registryConfig{
+		"matching host": {host: {insecureSkipVerify: true, clientCert: cert}},
+		"other host":    {host: {insecureSkipVerify: true}, "registry.example.com": {clientCert: cert}},
+	} {
+		client, err := authClient(clientConfig{network: "tcp", registries: registries})
+		if err != nil {
+			t.Fatal("authClient() error =", err)
+		}
+		resp, err := client.Client.Get(srv.URL)
+		if wantErr := name == "other host"; (err != nil) != wantErr {
+			t.Errorf("Get() with client certificate for %s error = %v, wantErr %v", name, err, wantErr)
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateClientCertificate(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, []byte(certPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(keyPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		authMap  map[string]any
+		wantCert bool
+		wantErr  bool
+	}{
+		{name: "none", authMap: map[string]any{}},
+		{name: "files", authMap: map[string]any{"client_cert_file": certFile, "client_key_file": keyFile}, wantCert: true},
+		{name: "pem", authMap: map[string]any{"client_cert_pem": certPEM, "client_key_pem": keyPEM}, wantCert: true},
+		{name: "mixed", authMap: map[string]any{"client_cert_file": certFile, "client_key_pem": keyPEM}, wantCert: true},
+		{name: "file and pem", authMap: map[string]any{"client_cert_file": certFile, "client_cert_pem": certPEM, "client_key_pem": keyPEM}, wantErr: true},
+		{name: "missing key", authMap: map[string]any{"client_cert_pem": certPEM}, wantErr: true},
+		{name: "mismatch", authMap: map[string]any{"client_cert_pem": certPEM, "client_key_pem": "invalid"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		cert, err := loadClientCertificate(tt.authMap)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("loadClientCertificate(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if (cert != nil) != tt.wantCert {
+			t.Errorf("loadClientCertificate(%s) = %v, want certificate %v", tt.name, cert, tt.wantCert)
+		}
+	}
+}
+
+// generateClientCertificate returns a PEM encoded self-signed client
+// certificate and its private key.
+func generateClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestAuthClient_proxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.Host
+	}))
+	defer proxy.Close()
+
+	client, err := authClient(clientConfig{
+		network: "tcp",
+		proxy:   &httpproxy.Config{HTTPProxy: proxy.URL},
+	})
+	if err != nil {
+		t.Fatal("authClient() error =", err)
+	}
+
+	resp, err := client.Client.Get("http://registry.example.com/v2/")
+	if err != nil {
+		t.Fatal("Get() error =", err)
+	}
+	resp.Body.Close()
+	select {
+	case host := <-proxied:
+		if host != "registry.example.com" {
+			t.Errorf("proxied request for %s, want registry.example.com", host)
+		}
+	default:
+		t.Error("request was not sent through the proxy")
+	}
+
+}
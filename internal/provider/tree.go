@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// maxTreeDepth is the number of directory levels exposed by the tree
+// attribute, as the schema of the nested entries can't be recursive.
+const maxTreeDepth = 8
+
+// treeSchema returns the schema of the entries of a directory tree, nesting
+// the entries of sub directories up to depth levels.
+func treeSchema(depth int) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the entry.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"path": {
+			Description: "The path of the entry, relative to `output_path` and using forward slashes.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"type": {
+			Description: "The type of the entry, `file`, `dir` or `symlink`.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"size": {
+			Description: "The size in bytes of a file, `0` for other entries.",
+			Type:        schema.TypeInt,
+			Computed:    true,
+		},
+	}
+	if depth > 1 {
+		s["children"] = &schema.Schema{
+			Description: "The entries of a directory, sorted by name.",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        treeSchema(depth - 1),
+		}
+	}
+	return &schema.Resource{Schema: s}
+}
+
+// buildTree returns the entries of dir, sorted by name, with the entries of
+// the sub directories nested up to depth levels.
+func buildTree(dir, rel string, depth int) ([]any, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	tree := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		p := path.Join(rel, entry.Name())
+		node := map[string]any{
+			"name": entry.Name(),
+			"path": p,
+			"size": 0,
+		}
+
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			node["type"] = "symlink"
+		case entry.IsDir():
+			node["type"] = "dir"
+			if depth > 1 {
+				children, err := buildTree(dir, p, depth-1)
+				if err != nil {
+					return nil, err
+				}
+				node["children"] = children
+			}
+		default:
+			node["type"] = "file"
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			node["size"] = info.Size()
+		}
+
+		tree = append(tree, node)
+	}
+	return tree, nil
+}
+
+// countFiles returns the number of files in dir and its sub directories, and
+// the total size of the regular files among them.
+func countFiles(dir string) (int, int64, error) {
+	var (
+		count int
+		size  int64
+	)
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		count++
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return count, size, err
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestBuildTree(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"b.txt":       "bb",
+		"a/x.yaml":    "x: 1",
+		"a/b/c/deep":  "deep",
+		"a/b/shallow": "s",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal("os.MkdirAll() error =", err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+
+	tree, err := buildTree(dir, "", 3)
+	if err != nil {
+		t.Fatal("buildTree() error =", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"tree": {Type: schema.TypeList, Computed: true, Elem: treeSchema(3)},
+	}, map[string]any{})
+	if err := d.Set("tree", tree); err != nil {
+		t.Fatal("ResourceData.Set() error =", err)
+	}
+
+	want := map[string]any{
+		"tree.#":                            2,
+		"tree.0.path":                       "a",
+		"tree.0.type":                       "dir",
+		"tree.0.children.#":                 2,
+		"tree.0.children.0.path":            "a/b",
+		"tree.0.children.0.children.#":      2,
+		"tree.0.children.0.children.0.path": "a/b/c",
+		"tree.0.children.0.children.1.size": 1,
+		"tree.0.children.1.name":            "x.yaml",
+		"tree.0.children.1.size":            4,
+		"tree.1.name":                       "b.txt",
+		"tree.1.type":                       "file",
+		"tree.1.size":                       2,
+	}
+	for key, value := range want {
+		if got := d.Get(key); got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+}
+
+func TestCountFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal("os.MkdirAll() error =", err)
+	}
+	for name, data := range map[string]string{"a.txt": "aaa", "sub/b.txt": "bb"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0o644); err != nil {
+			t.Fatal("os.WriteFile() error =", err)
+		}
+	}
+	if err := os.Symlink("a.txt", filepath.Join(dir, "link")); err != nil {
+		t.Fatal("os.Symlink() error =", err)
+	}
+
+	count, size, err := countFiles(dir)
+	if err != nil {
+		t.Fatal("countFiles() error =", err)
+	}
+	if count != 3 || size != 5 {
+		t.Errorf("countFiles() = %d, %d, want 3, 5", count, size)
+	}
+}
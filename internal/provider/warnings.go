@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// warningValueRegexp matches a Warning header value as defined by RFC 7234,
+// e.g. `299 - "this repository is deprecated"`.
+var warningValueRegexp = regexp.MustCompile(`^\d{3} \S+ "((?:[^"\\]|\\.)*)"`)
+
+type warningCollectorKey struct{}
+
+// warningCollector collects the Warning headers returned by registries while
+// reading a single data source or resource.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []registryWarning
+}
+
+type registryWarning struct {
+	host string
+	text string
+}
+
+func (c *warningCollector) add(w registryWarning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, w)
+}
+
+// reportedWarnings keeps track of the warnings already surfaced, so a warning
+// returned for every request is only reported once per run.
+type reportedWarnings struct {
+	mu   sync.Mutex
+	seen map[registryWarning]bool
+}
+
+// filter returns the warnings that were not reported before, in order and
+// without duplicates.
+func (r *reportedWarnings) filter(warnings []registryWarning) []registryWarning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen == nil {
+		r.seen = make(map[registryWarning]bool)
+	}
+
+	var filtered []registryWarning
+	for _, w := range warnings {
+		if !r.seen[w] {
+			r.seen[w] = true
+			filtered = append(filtered, w)
+		}
+	}
+	return filtered
+}
+
+// warningTransport records the Warning headers of responses in the collector
+// of the request context, if any.
+type warningTransport struct {
+	base http.RoundTripper
+}
+
+func (t *warningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := req.Context().Value(warningCollectorKey{}).(*warningCollector); ok {
+		for _, value := range resp.Header.Values("Warning") {
+			c.add(registryWarning{host: req.URL.Host, text: parseWarning(value)})
+		}
+	}
+	return resp, nil
+}
+
+// parseWarning returns the text of a Warning header value, or the value as is
+// when it is not formatted as defined by RFC 7234.
+func parseWarning(value string) string {
+	m := warningValueRegexp.FindStringSubmatch(value)
+	if m == nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(m[1], `\"`, `"`), `\\`, `\`)
+}
+
+// withRegistryWarnings wraps the functions of r so the Warning headers
+// returned by registries are surfaced as warning diagnostics.
+func withRegistryWarnings(r *schema.Resource) {
+	wrap := func(fn func(context.Context, *schema.ResourceData, any) diag.Diagnostics) func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			c, ok := meta.(*clients)
+			if !ok {
+				return fn(ctx, d, meta)
+			}
+
+			collector := &warningCollector{}
+			diags := fn(context.WithValue(ctx, warningCollectorKey{}, collector), d, meta)
+			for _, w := range c.reportedWarnings.filter(collector.warnings) {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("Registry %s returned a warning", w.host),
+					Detail:   w.text,
+				})
+			}
+			return diags
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseWarning(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`299 - "this repository is deprecated"`, "this repository is deprecated"},
+		{`299 registry.example.com "use \"new/repo\" instead" "Wed, 21 Oct 2015 07:28:00 GMT"`, `use "new/repo" instead`},
+		{" not formatted ", "not formatted"},
+	}
+	for _, tt := range tests {
+		if got := parseWarning(tt.value); got != tt.want {
+			t.Errorf("parseWarning(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWithRegistryWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Warning", `299 - "deprecated"`)
+		w.Header().Add("Warning", `299 - "deprecated"`)
+		w.Header().Add("Warning", `299 - "rate limited"`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &warningTransport{base: http.DefaultTransport}}
+	r := &schema.Resource{
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			resp.Body.Close()
+			return nil
+		},
+	}
+	withRegistryWarnings(r)
+
+	c := &clients{}
+	diags := r.ReadContext(context.Background(), nil, c)
+	if len(diags) != 2 || diags[0].Detail != "deprecated" || diags[1].Detail != "rate limited" {
+		t.Errorf("first read returned %v, want the two distinct warnings", diags)
+	}
+	if diags := r.ReadContext(context.Background(), nil, c); len(diags) != 0 {
+		t.Errorf("second read returned %v, want the warnings to be reported once", diags)
+	}
+}